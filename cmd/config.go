@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/internal/ui"
+	"github.com/safedep/xbom/pkg/config"
+	"github.com/safedep/xbom/pkg/reporter"
+	"github.com/spf13/pflag"
+)
+
+// markdownReportConfig and htmlReportConfig hold the snippet and section options
+// of the project config not overridden by flags
+var (
+	markdownReportConfig config.MarkdownReportConfig
+	htmlReportConfig     config.HTMLReportConfig
+)
+
+// markdownSectionNames are the values of --markdown-sections
+var markdownSectionNames = []string{
+	"executive-summary",
+	"statistics",
+	"top-signatures",
+	"language-breakdown",
+	"detailed-findings",
+}
+
+// applyProjectConfig sets the options of the project config file not set by flags.
+// The config file is discovered from the analysed directory unless given. Package
+// scans analyse a downloaded package, so their config file is never discovered.
+func applyProjectConfig(flags *pflag.FlagSet) error {
+	path := configPath
+	if path == "" {
+		if packageURL != "" {
+			return nil
+		}
+
+		discoveredPath, err := config.Discover(codeDirectory)
+		if err != nil {
+			return err
+		}
+
+		if discoveredPath == "" {
+			return nil
+		}
+
+		path = discoveredPath
+	}
+
+	projectConfig, err := config.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Using config file %s", path)
+	ui.Printf("⚙️  Using config %s\n", path)
+
+	configString(flags, "app-name", &appName, projectConfig.AppName)
+	configString(flags, "policy", &policyPath, projectConfig.Policy)
+	configString(flags, "baseline", &baselinePath, projectConfig.Baseline)
+
+	configStrings(flags, "signatures-dir", &signatureDirs, projectConfig.Signatures.Dirs)
+	configStrings(flags, "include-tags", &includeTags, projectConfig.Signatures.IncludeTags)
+	configStrings(flags, "exclude-tags", &excludeTags, projectConfig.Signatures.ExcludeTags)
+	configStrings(flags, "vendor", &signatureVendors, projectConfig.Signatures.Vendors)
+	configStrings(flags, "language", &signatureLanguages, projectConfig.Signatures.Languages)
+	configStrings(flags, "signature", &signatureIDPatterns, projectConfig.Signatures.IDs)
+	configStrings(flags, "include", &includePaths, projectConfig.Paths.Include)
+	configStrings(flags, "exclude", &excludePaths, projectConfig.Paths.Exclude)
+
+	reports := projectConfig.Reports
+	configString(flags, "bom", &cyclonedxReportPath, reports.CycloneDX.Path)
+	configString(flags, "report-html", &htmlReportPath, reports.HTML.Path)
+	configString(flags, "report-markdown", &markdownReportPath, reports.Markdown.Path)
+	configString(flags, "report-sarif", &sarifReportPath, reports.SARIF.Path)
+	configString(flags, "report-json", &jsonReportPath, reports.JSON.Path)
+	configString(flags, "report-jsonl", &jsonlReportPath, reports.JSONL.Path)
+
+	if limit := reports.Summary.Limit; limit != nil && !flags.Changed("summary-limit") {
+		summaryMaxResults = *limit
+	}
+
+	if stats := reports.Summary.Stats; stats != nil && !flags.Changed("summary-no-stats") {
+		summaryNoStats = !*stats
+	}
+
+	if groupBy := reports.Summary.GroupBy; groupBy != nil && !flags.Changed("summary-group-by") {
+		summaryGroupBy = *groupBy
+	}
+
+	if color := reports.Summary.Color; color != nil && !flags.Changed("summary-no-color") {
+		summaryNoColor = !*color
+	}
+
+	if reports.NoSnippets && !flags.Changed("no-snippets") {
+		noSnippets = true
+	}
+
+	markdownReportConfig = reports.Markdown
+	htmlReportConfig = reports.HTML
+
+	// Snippet flags apply to both reports
+	for _, snippet := range []*config.SnippetConfig{&markdownReportConfig.Snippet, &htmlReportConfig.Snippet} {
+		clearChanged(flags, "snippet-before-lines", &snippet.BeforeLines)
+		clearChanged(flags, "snippet-after-lines", &snippet.AfterLines)
+		clearChanged(flags, "snippet-max-bytes", &snippet.MaxBytes)
+		clearChanged(flags, "snippet-max-line-chars", &snippet.MaxLineChars)
+	}
+
+	if flags.Changed("markdown-sections") {
+		markdownReportConfig.Sections = config.MarkdownSectionsConfig{}
+	}
+
+	return nil
+}
+
+func configString(flags *pflag.FlagSet, name string, target *string, value string) {
+	if value != "" && !flags.Changed(name) {
+		*target = value
+	}
+}
+
+func configStrings(flags *pflag.FlagSet, name string, target *[]string, value []string) {
+	if len(value) > 0 && !flags.Changed(name) {
+		*target = value
+	}
+}
+
+// clearChanged drops a config option overridden by its flag
+func clearChanged(flags *pflag.FlagSet, name string, value **int) {
+	if flags.Changed(name) {
+		*value = nil
+	}
+}
+
+// snippetOption returns the option of the project config when set, else the flag value
+func snippetOption(value *int, flagValue int) int {
+	if value != nil {
+		return *value
+	}
+
+	return flagValue
+}
+
+func validateMarkdownSections(sections []string) error {
+	for _, section := range sections {
+		if !slices.Contains(markdownSectionNames, section) {
+			return fmt.Errorf("invalid Markdown section %q, expected one of %s",
+				section, strings.Join(markdownSectionNames, ", "))
+		}
+	}
+
+	return nil
+}
+
+// newHTMLReporterConfig creates the config of the HTML reporter
+// with the options of the flags and the project config
+func newHTMLReporterConfig() reporter.HTMLReporterConfig {
+	snippet := htmlReportConfig.Snippet
+
+	return reporter.HTMLReporterConfig{
+		HTMLReportPath:      htmlReportPath,
+		SnippetBeforeLines:  snippetOption(snippet.BeforeLines, snippetBeforeLines),
+		SnippetAfterLines:   snippetOption(snippet.AfterLines, snippetAfterLines),
+		SnippetMaxBytes:     snippetOption(snippet.MaxBytes, snippetMaxBytes),
+		SnippetMaxLineChars: snippetOption(snippet.MaxLineChars, snippetMaxLineChars),
+		NoSnippets:          noSnippets,
+		NoDefaults:          true,
+	}
+}
+
+// newMarkdownReporterConfig creates the config of the Markdown reporter
+// with the options of the flags and the project config
+func newMarkdownReporterConfig() reporter.MarkdownReporterConfig {
+	snippet := markdownReportConfig.Snippet
+	reporterConfig := reporter.MarkdownReporterConfig{
+		OutputPath:          markdownReportPath,
+		SnippetBeforeLines:  snippetOption(snippet.BeforeLines, snippetBeforeLines),
+		SnippetAfterLines:   snippetOption(snippet.AfterLines, snippetAfterLines),
+		SnippetMaxBytes:     snippetOption(snippet.MaxBytes, snippetMaxBytes),
+		SnippetMaxLineChars: snippetOption(snippet.MaxLineChars, snippetMaxLineChars),
+		NoSnippets:          noSnippets,
+		NoDefaults:          true,
+	}
+
+	sections := markdownReportConfig.Sections
+	if sections == (config.MarkdownSectionsConfig{}) {
+		reporterConfig.ShowExecutiveSummary = slices.Contains(markdownSections, "executive-summary")
+		reporterConfig.ShowStatistics = slices.Contains(markdownSections, "statistics")
+		reporterConfig.ShowTopSignatures = slices.Contains(markdownSections, "top-signatures")
+		reporterConfig.ShowLanguageBreakdown = slices.Contains(markdownSections, "language-breakdown")
+		reporterConfig.ShowDetailedFindings = slices.Contains(markdownSections, "detailed-findings")
+
+		return reporterConfig
+	}
+
+	// Sections not set are shown
+	shown := func(section *bool) bool {
+		return section == nil || *section
+	}
+
+	reporterConfig.ShowExecutiveSummary = shown(sections.ExecutiveSummary)
+	reporterConfig.ShowStatistics = shown(sections.Statistics)
+	reporterConfig.ShowTopSignatures = shown(sections.TopSignatures)
+	reporterConfig.ShowLanguageBreakdown = shown(sections.LanguageBreakdown)
+	reporterConfig.ShowDetailedFindings = shown(sections.DetailedFindings)
+
+	return reporterConfig
+}
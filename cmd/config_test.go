@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyProjectConfigSkipsDiscoveryForPackageScan(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".xbom.yaml"), []byte("app_name: project\n"), 0o644))
+
+	// Flags are registered first as they reset the options to their defaults
+	flags := NewGenerateCommand().Flags()
+
+	codeDirectory, packageURL = dir, "pkg:npm/example@1.0.0"
+	t.Cleanup(func() { codeDirectory, packageURL, appName = "", "", "" })
+
+	require.NoError(t, applyProjectConfig(flags))
+	assert.Empty(t, appName)
+
+	packageURL = ""
+
+	require.NoError(t, applyProjectConfig(flags))
+	assert.Equal(t, "project", appName)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/internal/analytics"
+	"github.com/safedep/xbom/internal/command"
+	"github.com/safedep/xbom/pkg/bomdiff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat        string
+	diffOutputPath    string
+	diffOldSourcePath string
+	diffNewSourcePath string
+)
+
+func NewDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff OLD_BOM NEW_BOM",
+		Short: "Compare components between two CycloneDX BOMs",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diff(args[0], args[1])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&diffFormat, "format", "f", bomdiff.FormatTable,
+		fmt.Sprintf("Output format (%s)", strings.Join(bomdiff.Formats(), ", ")))
+	cmd.Flags().StringVarP(&diffOutputPath, "output", "o", "",
+		"Write diff to file instead of stdout")
+	cmd.Flags().StringVarP(&diffOldSourcePath, "old-dir", "", "",
+		"Source directory of the old BOM, to compare evidence locations relative to it")
+	cmd.Flags().StringVarP(&diffNewSourcePath, "new-dir", "", "",
+		"Source directory of the new BOM, to compare evidence locations relative to it")
+
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		analytics.TrackCommandDiff()
+	}
+
+	return cmd
+}
+
+func diff(oldBomPath, newBomPath string) {
+	command.FailOnError("diff", internalDiff(oldBomPath, newBomPath))
+}
+
+func internalDiff(oldBomPath, newBomPath string) error {
+	if !slices.Contains(bomdiff.Formats(), diffFormat) {
+		return fmt.Errorf("%w: %s", bomdiff.ErrUnsupportedFormat, diffFormat)
+	}
+
+	oldBom, err := bomdiff.LoadBOM(oldBomPath)
+	if err != nil {
+		return err
+	}
+
+	newBom, err := bomdiff.LoadBOM(newBomPath)
+	if err != nil {
+		return err
+	}
+
+	result := bomdiff.Diff(oldBom, newBom, bomdiff.DiffConfig{
+		OldSourcePath: diffOldSourcePath,
+		NewSourcePath: diffNewSourcePath,
+	})
+
+	var w io.Writer = os.Stdout
+	if diffOutputPath != "" {
+		fd, err := os.Create(diffOutputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		defer func() {
+			if err := fd.Close(); err != nil {
+				log.Errorf("Failed to close file %s: %v", diffOutputPath, err)
+			}
+		}()
+
+		w = fd
+	}
+
+	err = bomdiff.Render(w, result, diffFormat)
+	if err != nil {
+		return err
+	}
+
+	if diffOutputPath != "" {
+		fmt.Printf("📄 Diff saved at %s\n", diffOutputPath)
+	}
+
+	return nil
+}
@@ -5,12 +5,26 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"time"
 
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/safedep/dry/log"
 	"github.com/safedep/xbom/internal/analytics"
 	"github.com/safedep/xbom/internal/command"
 	"github.com/safedep/xbom/internal/ui"
+	"github.com/safedep/xbom/pkg/baseline"
+	"github.com/safedep/xbom/pkg/bomdiff"
+	"github.com/safedep/xbom/pkg/cache"
 	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/config"
+	"github.com/safedep/xbom/pkg/gitdiff"
+	"github.com/safedep/xbom/pkg/pathfilter"
+	"github.com/safedep/xbom/pkg/policy"
 	"github.com/safedep/xbom/pkg/reporter"
 	"github.com/safedep/xbom/pkg/signatures"
 	"github.com/spf13/cobra"
@@ -21,11 +35,45 @@ var (
 	appName             string
 	codeDirectory       string
 	cyclonedxReportPath string
+	enrichBomPath       string
 	htmlReportPath      string
 	markdownReportPath  string
+	sarifReportPath     string
+	jsonReportPath      string
+	jsonlReportPath     string
+	policyPath          string
+	baselinePath        string
+	writeBaselinePath   string
+	ignoreInlineSuppr   bool
+	sinceRevision       string
+	includePaths        []string
+	excludePaths        []string
+	noDefaultExcludes   bool
+	noIgnoreFiles       bool
+	analysisTimeout     time.Duration
+	concurrency         int
+	maxFileSize         int64
+	fileTimeout         time.Duration
+	cacheDir            string
+	noCache             bool
+	partialResults      bool
+	configPath          string
 	summaryMaxResults   int
 	summaryNoStats      bool
 	summaryNoColor      bool
+	summaryGroupBy      string
+	snippetBeforeLines  int
+	snippetAfterLines   int
+	snippetMaxBytes     int
+	snippetMaxLineChars int
+	noSnippets          bool
+	markdownSections    []string
+	signatureDirs       []string
+	includeTags         []string
+	excludeTags         []string
+	signatureVendors    []string
+	signatureLanguages  []string
+	signatureIDPatterns []string
 )
 
 func NewGenerateCommand() *cobra.Command {
@@ -33,6 +81,8 @@ func NewGenerateCommand() *cobra.Command {
 		Use:   "generate",
 		Short: "Generate BOMs enriched with AI, ML, SaaS, Cloud and more",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("config", applyProjectConfig(cmd.Flags()))
+
 			generate()
 			return nil
 		},
@@ -45,27 +95,101 @@ func NewGenerateCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&codeDirectory, "dir", "D", wd,
 		"Directory for analysing and generating BOM")
+	cmd.Flags().StringVarP(&configPath, "config", "", "",
+		"Project config file (default is "+config.FileName+" in the directory or its closest parent having one)")
 	cmd.Flags().StringVarP(&packageURL, "purl", "P", "",
 		"Package URL of a supported OSS package (eg. pkg:/npm/express@4.17.1")
 	cmd.Flags().StringVarP(&appName, "app-name", "", "",
 		"App name to include in CycloneDX BOM")
 	cmd.Flags().StringVarP(&cyclonedxReportPath, "bom", "", "",
 		"Generate CycloneDX BOM to file")
+	cmd.Flags().StringVarP(&enrichBomPath, "enrich-bom", "", "",
+		"Enrich an existing CycloneDX BOM in JSON format instead of creating a new one, requires --bom")
 	cmd.Flags().StringVarP(&htmlReportPath, "report-html", "", "",
 		"Generate HTML report to file")
 	cmd.Flags().StringVarP(&markdownReportPath, "report-markdown", "", "",
 		"Generate Markdown report to file")
+	cmd.Flags().StringVarP(&sarifReportPath, "report-sarif", "", "",
+		"Generate SARIF report to file")
+	cmd.Flags().StringVarP(&jsonReportPath, "report-json", "", "",
+		"Generate JSON report to file")
+	cmd.Flags().StringVarP(&jsonlReportPath, "report-jsonl", "", "",
+		"Stream findings as JSON Lines to file while analysing, in the order files are analysed which varies with --concurrency")
+	cmd.Flags().StringVarP(&policyPath, "policy", "", "",
+		"Policy file with rules to evaluate on findings, exits with non-zero code on violations")
+	cmd.Flags().StringVarP(&baselinePath, "baseline", "", "",
+		"Baseline file with known findings to suppress")
+	cmd.Flags().StringVarP(&writeBaselinePath, "write-baseline", "", "",
+		"Write fingerprints of all findings to a baseline file")
+	cmd.Flags().StringVarP(&sinceRevision, "since", "", "",
+		"Analyse only files changed since the git revision and tag findings as introduced or pre-existing")
+	cmd.Flags().BoolVarP(&ignoreInlineSuppr, "ignore-inline-suppressions", "", false,
+		"Ignore xbom:ignore suppression comments in code")
 	cmd.Flags().IntVarP(&summaryMaxResults, "summary-limit", "", 20,
 		"Maximum number of results to display in summary (0 for unlimited)")
 	cmd.Flags().BoolVarP(&summaryNoStats, "summary-no-stats", "", false,
 		"Disable statistics panel in summary output")
 	cmd.Flags().BoolVarP(&summaryNoColor, "summary-no-color", "", false,
 		"Disable colored output in summary")
+	cmd.Flags().StringVarP(&summaryGroupBy, "summary-group-by", "", "",
+		"Group results in summary by signature, language or file")
+
+	defaultSnippet := reporter.DefaultMarkdownReporterConfig()
+	cmd.Flags().IntVarP(&snippetBeforeLines, "snippet-before-lines", "", defaultSnippet.SnippetBeforeLines,
+		"Number of context lines before a match in HTML and Markdown snippets")
+	cmd.Flags().IntVarP(&snippetAfterLines, "snippet-after-lines", "", defaultSnippet.SnippetAfterLines,
+		"Number of context lines after a match in HTML and Markdown snippets")
+	cmd.Flags().IntVarP(&snippetMaxBytes, "snippet-max-bytes", "", defaultSnippet.SnippetMaxBytes,
+		"Maximum size in bytes of HTML and Markdown snippets (0 for no limit)")
+	cmd.Flags().IntVarP(&snippetMaxLineChars, "snippet-max-line-chars", "", defaultSnippet.SnippetMaxLineChars,
+		"Maximum characters per line of HTML and Markdown snippets (0 for no limit)")
+	cmd.Flags().BoolVarP(&noSnippets, "no-snippets", "", false,
+		"Omit code snippets from all reports, findings are reported with their location only")
+	cmd.Flags().StringSliceVarP(&markdownSections, "markdown-sections", "", markdownSectionNames,
+		"Sections of the Markdown report to show")
+
+	cmd.Flags().StringArrayVarP(&signatureDirs, "signatures-dir", "", []string{},
+		"Directory with additional signatures to load along with the embedded signatures (can be repeated)")
+	cmd.Flags().StringSliceVarP(&includeTags, "include-tags", "", []string{},
+		"Match only signatures having any of the tags (eg. ai,llm)")
+	cmd.Flags().StringSliceVarP(&excludeTags, "exclude-tags", "", []string{},
+		"Skip signatures having any of the tags (eg. capability)")
+	cmd.Flags().StringSliceVarP(&signatureVendors, "vendor", "", []string{},
+		"Match only signatures from the vendors (eg. openai)")
+	cmd.Flags().StringSliceVarP(&signatureLanguages, "language", "", []string{},
+		"Match only signatures for the languages (eg. python)")
+	cmd.Flags().StringSliceVarP(&signatureIDPatterns, "signature", "", []string{},
+		"Match only signatures with ID matching the glob patterns (eg. 'langchain.*')")
+
+	cmd.Flags().StringSliceVarP(&includePaths, "include", "", []string{},
+		"Analyse only files matching the gitignore style patterns (eg. 'src/**')")
+	cmd.Flags().StringSliceVarP(&excludePaths, "exclude", "", []string{},
+		"Skip files and directories matching the gitignore style patterns (eg. 'test/fixtures/')")
+	cmd.Flags().BoolVarP(&noDefaultExcludes, "no-default-excludes", "", false,
+		"Analyse directories excluded by default such as node_modules and vendor")
+	cmd.Flags().BoolVarP(&noIgnoreFiles, "no-ignore-files", "", false,
+		"Ignore .gitignore and .xbomignore files")
+
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "", 0,
+		"Number of files to analyse concurrently (0 for number of CPUs)")
+	cmd.Flags().DurationVarP(&analysisTimeout, "timeout", "", 0,
+		"Abort the analysis after the duration (eg. 10m, 0 for no limit)")
+	cmd.Flags().Int64VarP(&maxFileSize, "max-file-size", "", 2*1024*1024,
+		"Skip analysis of files larger than the size in bytes (0 for no limit)")
+	cmd.Flags().DurationVarP(&fileTimeout, "file-timeout", "", 30*time.Second,
+		"Skip analysis of a file not analysed within the duration (0 for no limit)")
+	cmd.Flags().StringVarP(&cacheDir, "cache-dir", "", "",
+		"Directory of the analysis cache (default is xbom in the user cache directory)")
+	cmd.Flags().BoolVarP(&noCache, "no-cache", "", false,
+		"Analyse all files without reusing or caching results of earlier analyses")
+	cmd.Flags().BoolVarP(&partialResults, "partial-results", "", analytics.RunningInCI(),
+		fmt.Sprintf("Continue when the analysis of a file fails and exit with code %d after reporting (default true in CI)",
+			command.ExitCodePartialResults))
 
 	// Add validations that should trigger a fail fast condition
 	cmd.PreRun = func(cmd *cobra.Command, args []string) {
 		err := func() error {
-			return nil
+			return validateMarkdownSections(markdownSections)
 		}()
 
 		command.FailOnError("pre-scan", err)
@@ -76,15 +200,23 @@ func NewGenerateCommand() *cobra.Command {
 
 func generate() {
 	analytics.TrackCommandGenerate()
-	command.FailOnError("generate", internalGenerateMulti())
+
+	ctx := context.Background()
+	if analysisTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, analysisTimeout)
+		defer cancel()
+	}
+
+	command.FailOnError("generate", internalGenerateMulti(ctx))
 }
 
 // internalGenerateMulti handles multiple input adapters before invoking the
 // core scanning workflow
-func internalGenerateMulti() error {
+func internalGenerateMulti(ctx context.Context) error {
 	// Start with different supported adapters based on args
 	if packageURL != "" {
-		return internalGeneratePurl()
+		return internalGeneratePurl(ctx)
 	}
 
 	// Fallback to the last option ie. local directory
@@ -92,14 +224,14 @@ func internalGenerateMulti() error {
 		appName = path.Base(codeDirectory)
 	}
 
-	return internalGenerateDirectory(appName, codeDirectory)
+	return internalGenerateDirectory(ctx, appName, codeDirectory)
 }
 
 // internalGeneratePurl setup a local cache for a package
 // identified by its PURL for scanning. It also cleanup the local
 // cache after the scanning process.
-func internalGeneratePurl() error {
-	pullResponse, err := command.PackagePull(context.Background(), command.PackagePullRequest{
+func internalGeneratePurl(ctx context.Context) error {
+	pullResponse, err := command.PackagePull(ctx, command.PackagePullRequest{
 		PURL: packageURL,
 	})
 	if err != nil {
@@ -121,38 +253,89 @@ func internalGeneratePurl() error {
 		appName = packageURL
 	}
 
-	return internalGenerateDirectory(appName, localPath)
+	return internalGenerateDirectory(ctx, appName, localPath)
 }
 
 // internalGenerate executes the core scanning workflow to generate an XBOM report
-func internalGenerateDirectory(appName, codeDir string) error {
+func internalGenerateDirectory(ctx context.Context, appName, codeDir string) error {
 	log.Infof("Generating BOM for source - %s", codeDir)
 
-	// provide grouping filters using signatures.LoadSignatures("microsoft", "azure", "servicebus")
-	signaturesToMatch, err := signatures.LoadAllSignatures()
+	loadedSignatures, signatureMetadata, err := signatures.LoadAllSignaturesWithMetadata(signatureDirs)
 	if err != nil {
 		return fmt.Errorf("failed to load signatures: %w", err)
 	}
 
-	log.Debugf("Loaded %d signatures", len(signaturesToMatch))
+	log.Debugf("Loaded %d signatures", len(loadedSignatures))
+
+	signaturesToMatch, err := signatures.FilterSignatures(loadedSignatures, signatures.SignatureFilter{
+		IncludeTags: includeTags,
+		ExcludeTags: excludeTags,
+		Vendors:     signatureVendors,
+		Languages:   signatureLanguages,
+		IDPatterns:  signatureIDPatterns,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to filter signatures: %w", err)
+	}
+
+	if len(signaturesToMatch) == 0 {
+		return fmt.Errorf("no signatures selected by the signature filters")
+	}
+
+	log.Debugf("Selected %d signatures to match", len(signaturesToMatch))
+
+	// Compile the policy before analysis to fail fast on invalid rules
+	var policyEvaluator *policy.Evaluator
+	if policyPath != "" {
+		loadedPolicy, err := policy.LoadPolicy(policyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load policy: %w", err)
+		}
+
+		policyEvaluator, err = policy.NewEvaluator(loadedPolicy, policy.EvaluatorConfig{
+			SourcePath: codeDir,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create policy evaluator: %w", err)
+		}
+	}
 
 	reporters := []reporter.Reporter{}
 
 	summaryReporter, err := reporter.NewSummaryReporter(reporter.SummaryReporterConfig{
 		MaxResults: summaryMaxResults,
 		ShowStats:  !summaryNoStats,
+		GroupBy:    summaryGroupBy,
 		Colorize:   !summaryNoColor,
+		SourcePath: codeDir,
+		NoDefaults: true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create summary reporter: %w", err)
 	}
 	reporters = append(reporters, summaryReporter)
 
+	if enrichBomPath != "" && cyclonedxReportPath == "" {
+		return fmt.Errorf("--enrich-bom requires --bom to write the enriched BOM")
+	}
+
 	if cyclonedxReportPath != "" {
+		var inputBom *cdx.BOM
+		if enrichBomPath != "" {
+			inputBom, err = bomdiff.LoadBOM(enrichBomPath)
+			if err != nil {
+				return fmt.Errorf("failed to load BOM to enrich: %w", err)
+			}
+		}
+
 		cdxReporter, err := reporter.NewCycloneDXBomReporter(reporter.CycloneDXReporterConfig{
 			Tool:                     xbomTool,
 			Path:                     cyclonedxReportPath,
 			ApplicationComponentName: appName,
+			ServiceMetadata:          signatureMetadata.Services,
+			ModelMetadata:            signatureMetadata.Models,
+			SourcePath:               codeDir,
+			InputBOM:                 inputBom,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create CycloneDX reporter: %w", err)
@@ -161,9 +344,7 @@ func internalGenerateDirectory(appName, codeDir string) error {
 	}
 
 	if htmlReportPath != "" {
-		htmlReporter, err := reporter.NewHTMLReporter(reporter.HTMLReporterConfig{
-			HTMLReportPath: htmlReportPath,
-		})
+		htmlReporter, err := reporter.NewHTMLReporter(newHTMLReporterConfig())
 		if err != nil {
 			return fmt.Errorf("failed to create HTML reporter: %w", err)
 		}
@@ -171,32 +352,128 @@ func internalGenerateDirectory(appName, codeDir string) error {
 	}
 
 	if markdownReportPath != "" {
-		markdownReporter, err := reporter.NewMarkdownReporter(reporter.MarkdownReporterConfig{
-			OutputPath: markdownReportPath,
-		})
+		markdownReporter, err := reporter.NewMarkdownReporter(newMarkdownReporterConfig())
 		if err != nil {
 			return fmt.Errorf("failed to create Markdown reporter: %w", err)
 		}
 		reporters = append(reporters, markdownReporter)
 	}
 
+	if sarifReportPath != "" {
+		sarifReporter, err := reporter.NewSARIFReporter(reporter.SARIFReporterConfig{
+			Tool:       xbomTool,
+			Path:       sarifReportPath,
+			SourcePath: codeDir,
+			NoSnippets: noSnippets,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create SARIF reporter: %w", err)
+		}
+		reporters = append(reporters, sarifReporter)
+	}
+
+	if jsonReportPath != "" {
+		jsonReporter, err := reporter.NewJSONReporter(reporter.JSONReporterConfig{
+			Tool:       xbomTool,
+			Path:       jsonReportPath,
+			SourcePath: codeDir,
+			NoSnippets: noSnippets,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create JSON reporter: %w", err)
+		}
+		reporters = append(reporters, jsonReporter)
+	}
+
+	if jsonlReportPath != "" {
+		jsonlReporter, err := reporter.NewJSONLReporter(reporter.JSONLReporterConfig{
+			Path:       jsonlReportPath,
+			SourcePath: codeDir,
+			NoSnippets: noSnippets,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create JSONL reporter: %w", err)
+		}
+
+		// Finish closes the report, this closes it when the analysis fails
+		defer func() {
+			if err := jsonlReporter.Close(); err != nil {
+				log.Errorf("Failed to close JSONL report: %v", err)
+			}
+		}()
+
+		reporters = append(reporters, jsonlReporter)
+	}
+
+	suppressors := []codeanalysis.Suppressor{}
+	if !ignoreInlineSuppr {
+		suppressors = append(suppressors, codeanalysis.NewInlineSuppressor())
+	}
+
+	if baselinePath != "" {
+		loadedBaseline, err := baseline.LoadBaseline(baselinePath)
+		if err != nil {
+			return fmt.Errorf("failed to load baseline: %w", err)
+		}
+
+		log.Debugf("Loaded %d baseline findings", len(loadedBaseline.Findings))
+
+		suppressors = append(suppressors, baseline.NewSuppressor(loadedBaseline, codeDir))
+	}
+
+	pathExcluder, err := pathfilter.NewFilter(newPathFilterConfig(codeDir))
+	if err != nil {
+		return fmt.Errorf("failed to create path filter: %w", err)
+	}
+
+	var pathFilter func(string) bool
+	var diffClassifier codeanalysis.DiffClassifier
+	if sinceRevision != "" {
+		changes, classifier, err := prepareDifferentialScan(ctx, codeDir, sinceRevision, signaturesToMatch)
+		if err != nil {
+			return fmt.Errorf("failed to prepare differential scan: %w", err)
+		}
+
+		pathFilter = changes.Contains
+		diffClassifier = classifier
+	}
+
 	workflow := codeanalysis.NewCodeAnalysisWorkflow(
 		codeanalysis.CodeAnalysisWorkflowConfig{
 			Tool:              xbomTool,
 			SourcePath:        codeDir,
 			SignaturesToMatch: signaturesToMatch,
+			Suppressors:       suppressors,
+			PathExcluder:      pathExcluder,
+			PathFilter:        pathFilter,
+			DiffClassifier:    diffClassifier,
+			Concurrency:       concurrency,
+			MaxFileSize:       maxFileSize,
+			FileTimeout:       fileTimeout,
+			Cache:             newMatchResultCache(signaturesToMatch),
+			PartialResults:    partialResults,
 			Callbacks: codeanalysis.CodeAnalysisCallbackRegistry{
 				OnStart: func() error {
-					ui.StartSpinner("Analyzing code")
+					ui.StartProgress("Analyzing code")
 					return nil
 				},
+				OnFilesEnumerated: ui.SetProgressTotal,
+				OnFileDone: func(string) {
+					ui.IncrementProgress()
+				},
+				OnFileSkipped: func(common.SkippedFile) {
+					ui.IncrementProgress()
+				},
+				OnFileFailed: func(common.FailedFile) {
+					ui.IncrementProgress()
+				},
 				OnFinish: func() error {
-					ui.StopSpinner("✅ Code analysis completed.")
+					ui.StopProgress("✅ Code analysis completed.")
 					return nil
 				},
 				OnErr: func(message string, err error) {
 					log.Errorf("Error in code analysis workflow: %s: %v", message, err)
-					ui.StopSpinner(fmt.Sprintf("❗Code analysis failed with error: %s", err.Error()))
+					ui.StopProgress(fmt.Sprintf("❗Code analysis failed with error: %s", err.Error()))
 				},
 			},
 		},
@@ -204,11 +481,38 @@ func internalGenerateDirectory(appName, codeDir string) error {
 	)
 
 	// If xbom is used as a library, we may use the finalized findings here
-	_, err = workflow.Execute()
+	findings, err := workflow.ExecuteContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to execute code analysis workflow: %w", err)
 	}
 
+	if writeBaselinePath != "" {
+		if findings.Partial() {
+			log.Warnf("Baseline misses the findings of %d files whose analysis failed", len(findings.FailedFiles))
+		}
+
+		err = baseline.NewBaseline(xbomTool, codeDir, findings).Write(writeBaselinePath)
+		if err != nil {
+			return fmt.Errorf("failed to write baseline: %w", err)
+		}
+
+		ui.Printf("📄 Baseline saved at %s\n", writeBaselinePath)
+	}
+
+	if policyEvaluator != nil {
+		violations, err := policyEvaluator.Evaluate(findings)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate policy: %w", err)
+		}
+
+		renderPolicyViolations(violations)
+
+		if len(violations) > 0 {
+			return command.NewExitError(command.ExitCodePolicyViolation,
+				fmt.Errorf("%w: %d violations found", policy.ErrPolicyViolation, len(violations)))
+		}
+	}
+
 	// Nudge user to visualise the results
 	if htmlReportPath == "" && markdownReportPath == "" {
 		ui.Println()
@@ -218,5 +522,163 @@ func internalGenerateDirectory(appName, codeDir string) error {
 		ui.Println("  xbom generate --report-markdown /tmp/report.md")
 	}
 
+	if findings.Partial() {
+		return command.NewExitError(command.ExitCodePartialResults,
+			fmt.Errorf("analysis of %d files failed, results are incomplete", len(findings.FailedFiles)))
+	}
+
 	return nil
 }
+
+// newPathFilterConfig creates the config of the path filter. Packages pulled from a
+// registry commonly ship their only code in build output directories, which may also
+// be listed in their ignore files, so neither apply to package scans.
+func newPathFilterConfig(codeDir string) pathfilter.FilterConfig {
+	packageScan := packageURL != ""
+
+	return pathfilter.FilterConfig{
+		SourcePath:      codeDir,
+		Include:         includePaths,
+		Exclude:         excludePaths,
+		DefaultExcludes: !noDefaultExcludes && !packageScan,
+		IgnoreFiles:     !noIgnoreFiles && !packageScan,
+	}
+}
+
+// newMatchResultCache creates the analysis cache unless disabled. The analysis
+// runs without a cache when it cannot be created.
+func newMatchResultCache(signaturesToMatch []*callgraphv1.Signature) codeanalysis.MatchResultCache {
+	if noCache {
+		return nil
+	}
+
+	dir := cacheDir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			log.Warnf("Analysis cache disabled, user cache directory not found: %v", err)
+			return nil
+		}
+
+		dir = filepath.Join(userCacheDir, "xbom")
+	}
+
+	fileCache, err := cache.NewFileCache(cache.FileCacheConfig{
+		Dir:        dir,
+		Tool:       xbomTool,
+		Signatures: signaturesToMatch,
+	})
+	if err != nil {
+		log.Warnf("Analysis cache disabled: %v", err)
+		return nil
+	}
+
+	removed, err := fileCache.Prune()
+	if err != nil {
+		log.Warnf("Failed to prune analysis cache: %v", err)
+	}
+
+	log.Debugf("Pruned %d unused entries of the analysis cache", removed)
+
+	return fileCache
+}
+
+// prepareDifferentialScan finds the files changed since a git revision and scans
+// their base revision, so that findings in the changed files can be classified
+// as introduced or pre-existing
+func prepareDifferentialScan(ctx context.Context, codeDir, revision string,
+	signaturesToMatch []*callgraphv1.Signature,
+) (*gitdiff.Changes, codeanalysis.DiffClassifier, error) {
+	changes, err := gitdiff.ChangedFiles(codeDir, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ui.Printf("🔀 Analysing %d files changed since %s (merge base %s)\n",
+		len(changes.Files), revision, changes.BaseCommit)
+
+	baseDir, err := os.MkdirTemp("", "xbom-base-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory for base revision: %w", err)
+	}
+
+	defer func() {
+		if err := os.RemoveAll(baseDir); err != nil {
+			log.Warnf("Failed to remove directory %s: %v", baseDir, err)
+		}
+	}()
+
+	err = changes.ExtractBase(baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseWorkflow := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              xbomTool,
+			SourcePath:        baseDir,
+			SignaturesToMatch: signaturesToMatch,
+			Concurrency:       concurrency,
+			MaxFileSize:       maxFileSize,
+			FileTimeout:       fileTimeout,
+			PartialResults:    partialResults,
+		},
+		nil,
+	)
+
+	baseFindings, err := baseWorkflow.ExecuteContext(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to analyse base revision: %w", err)
+	}
+
+	if baseFindings.Partial() {
+		log.Warnf("Analysis of %d files of the base revision failed, their findings are classified as introduced",
+			len(baseFindings.FailedFiles))
+	}
+
+	return changes, gitdiff.NewClassifier(baseFindings, baseDir, codeDir), nil
+}
+
+// renderPolicyViolations prints the violations along with the rule each one broke
+func renderPolicyViolations(violations []policy.Violation) {
+	ui.Println()
+
+	if len(violations) == 0 {
+		msg := "✓ No policy violations found!"
+		if !summaryNoColor {
+			msg = color.New(color.FgGreen, color.Bold).Sprint(msg)
+		}
+
+		ui.Println(msg)
+		return
+	}
+
+	violationTable := table.NewWriter()
+	violationTable.SetOutputMirror(os.Stdout)
+	violationTable.SetStyle(table.StyleRounded)
+	violationTable.SetTitle("🚫 Policy Violations")
+	violationTable.AppendHeader(table.Row{"#", "Rule", "Signature", "File", "Line"})
+
+	for i, violation := range violations {
+		rule := violation.Rule.Name
+		if violation.Rule.Description != "" {
+			rule = fmt.Sprintf("%s\n%s", rule, violation.Rule.Description)
+		}
+
+		line := "-"
+		if violation.Line > 0 {
+			line = fmt.Sprintf("L%d", violation.Line)
+		}
+
+		violationTable.AppendRow(table.Row{i + 1, rule, violation.SignatureID, violation.FilePath, line})
+		violationTable.AppendSeparator()
+	}
+
+	violationTable.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, WidthMax: 40},
+		{Number: 3, WidthMax: 40},
+		{Number: 4, WidthMax: 40},
+	})
+
+	violationTable.Render()
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/pathfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPathFilterConfig(t *testing.T) {
+	// A package as published to a registry, with its code in dist/
+	// which is listed in its ignore file
+	packageDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(packageDir, "dist"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(packageDir, ".gitignore"), []byte("dist/\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(packageDir, "dist", "index.js"), []byte("require('openai')\n"), 0o644))
+
+	cases := []struct {
+		name       string
+		packageURL string
+		excluded   bool
+	}{
+		{"directory scan", "", true},
+		{"package scan", "pkg:npm/example@1.0.0", false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			packageURL = test.packageURL
+			t.Cleanup(func() { packageURL = "" })
+
+			filter, err := pathfilter.NewFilter(newPathFilterConfig(packageDir))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.excluded, filter.Excluded("dist", true))
+			assert.Equal(t, test.excluded, filter.Excluded("dist/index.js", false))
+		})
+	}
+}
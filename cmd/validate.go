@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var validateSignatureDirs []string
+
 func NewValidateCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
@@ -19,6 +21,9 @@ func NewValidateCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringArrayVarP(&validateSignatureDirs, "signatures-dir", "", []string{},
+		"Directory with additional signatures to validate along with the embedded signatures (can be repeated)")
+
 	// Add validations that should trigger a fail fast condition
 	cmd.PreRun = func(cmd *cobra.Command, args []string) {
 		analytics.TrackCommandValidate()
@@ -38,7 +43,7 @@ func validate() {
 }
 
 func internalValidate() error {
-	_, err := signatures.LoadAllSignatures()
+	_, err := signatures.LoadAllSignaturesWithDirs(validateSignatureDirs)
 	if err == nil {
 		fmt.Println("✅ Signatures valid")
 	} else {
@@ -29,3 +29,22 @@ func TestCloseIsImmutable(t *testing.T) {
 	Close()
 	assert.Nil(t, globalPosthogClient)
 }
+
+func TestRunningInCI(t *testing.T) {
+	for envVar := range ciEnvVars {
+		t.Setenv(envVar, "")
+	}
+
+	t.Setenv("CI", "")
+	assert.False(t, RunningInCI())
+
+	t.Setenv("CI", "false")
+	assert.False(t, RunningInCI())
+
+	t.Setenv("CI", "true")
+	assert.True(t, RunningInCI())
+
+	t.Setenv("CI", "")
+	t.Setenv("GITLAB_CI", "true")
+	assert.True(t, RunningInCI())
+}
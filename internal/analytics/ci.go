@@ -18,6 +18,22 @@ var ciEnvVars = map[string]environmentType{
 	"CI_COMMIT_BRANCH": environmentTypeGitLabCI,
 }
 
+// RunningInCI returns true when running in a CI environment. Most CI
+// services, including GitHub Actions and GitLab CI, set the CI variable.
+func RunningInCI() bool {
+	if ci := os.Getenv("CI"); ci != "" && ci != "false" && ci != "0" {
+		return true
+	}
+
+	for envVar := range ciEnvVars {
+		if os.Getenv(envVar) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func TrackCI() {
 	uniqueTypes := make(map[environmentType]bool)
 	for envVar, envVarType := range ciEnvVars {
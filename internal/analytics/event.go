@@ -4,6 +4,7 @@ const (
 	eventRun             = "xbom_command_run"
 	eventCommandGenerate = "xbom_command_generate"
 	eventCommandValidate = "xbom_command_validate"
+	eventCommandDiff     = "xbom_command_diff"
 
 	eventXbomGenerateEnvDocker        = "xbom_command_generate_env_docker"
 	eventXbomGenerateEnvGitHubActions = "xbom_command_generate_env_github_actions"
@@ -22,6 +23,10 @@ func TrackCommandValidate() {
 	TrackEvent(eventCommandValidate)
 }
 
+func TrackCommandDiff() {
+	TrackEvent(eventCommandDiff)
+}
+
 func TrackCommandGenerateEnvDocker() {
 	TrackEvent(eventXbomGenerateEnvDocker)
 }
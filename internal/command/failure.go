@@ -1,11 +1,49 @@
 package command
 
 import (
+	"errors"
 	"log"
+	"os"
 )
 
+// Process exit codes used by commands
+const (
+	ExitCodeFailure         = 1
+	ExitCodePolicyViolation = 2
+
+	// ExitCodePartialResults is used when all reports were generated
+	// but the analysis of some files failed
+	ExitCodePartialResults = 3
+)
+
+// ExitError is an error that must terminate the process with a specific exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func FailOnError(stage string, err error) {
-	if err != nil {
-		log.Fatalf("Execution failed [%s] Error: %v", stage, err)
+	if err == nil {
+		return
 	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		log.Printf("Execution failed [%s] Error: %v", stage, err)
+		os.Exit(exitErr.Code)
+	}
+
+	log.Fatalf("Execution failed [%s] Error: %v", stage, err)
 }
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressBarWidth = 30
+
+// progress is rendered as a spinner until the total is known
+// and as a progress bar with an ETA afterwards
+type progress struct {
+	mutex     sync.Mutex
+	msg       string
+	total     int
+	done      int
+	startedAt time.Time
+	stop      chan bool
+	stopped   chan bool
+}
+
+var currentProgress *progress
+
+// StartProgress shows a spinner with the message until
+// the total is set using SetProgressTotal
+func StartProgress(msg string) {
+	p := &progress{
+		msg:     msg,
+		stop:    make(chan bool),
+		stopped: make(chan bool),
+	}
+
+	currentProgress = p
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	go func() {
+		defer close(p.stopped)
+
+		pos := 0
+		for {
+			select {
+			case <-p.stop:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				fmt.Print("\033[2K\r" + p.render(pos))
+				pos += 1
+			}
+		}
+	}()
+}
+
+// SetProgressTotal switches the progress to a progress bar of total steps
+func SetProgressTotal(total int) {
+	if p := currentProgress; p != nil {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		p.total = total
+		p.startedAt = time.Now()
+	}
+}
+
+// IncrementProgress marks a step of the progress bar as done
+func IncrementProgress() {
+	if p := currentProgress; p != nil {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		p.done++
+	}
+}
+
+// StopProgress replaces the progress with the message. It can be called
+// multiple times, the message is shown every time.
+func StopProgress(stopMsg string) {
+	if p := currentProgress; p != nil {
+		currentProgress = nil
+
+		close(p.stop)
+		<-p.stopped
+	}
+
+	// Clears current line and moves cursor to the beginning
+	fmt.Println("\033[2K\r" + stopMsg)
+}
+
+func (p *progress) render(pos int) string {
+	style := `⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏`
+	frames := []rune(style)
+	frame := string(frames[pos%len(frames)])
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.total <= 0 {
+		return fmt.Sprintf("%s ... %s", p.msg, frame)
+	}
+
+	done := min(p.done, p.total)
+	filled := progressBarWidth * done / p.total
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	return fmt.Sprintf("%s %s %s %d/%d files %s", frame, p.msg, bar, done, p.total,
+		progressETA(time.Since(p.startedAt), done, p.total))
+}
+
+// progressETA estimates the remaining time assuming the
+// remaining steps take as long as the steps done so far
+func progressETA(elapsed time.Duration, done, total int) string {
+	if done == 0 {
+		return "ETA --"
+	}
+
+	if done >= total {
+		return "ETA 0s"
+	}
+
+	remaining := time.Duration(float64(elapsed) / float64(done) * float64(total-done))
+	return "ETA " + remaining.Round(time.Second).String()
+}
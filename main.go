@@ -53,6 +53,7 @@ func main() {
 	command.AddCommand(cmd.NewVersionCommand())
 	command.AddCommand(cmd.NewGenerateCommand())
 	command.AddCommand(cmd.NewValidateCommand())
+	command.AddCommand(cmd.NewDiffCommand())
 
 	// Print banner on --help / -h
 	command.SetHelpFunc(func(command *cobra.Command, args []string) {
@@ -0,0 +1,169 @@
+// Package baseline implements fingerprinting of findings and suppression
+// of findings recorded in a baseline file. This allows reviewing only the
+// findings introduced after the baseline was created.
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// BaselineVersion is the version of the baseline file format
+const BaselineVersion = "1.0.0"
+
+// SuppressionSource is the source of suppressions created from a baseline
+const SuppressionSource = "baseline"
+
+var (
+	ErrReadBaseline  = errors.New("failed to read baseline")
+	ErrWriteBaseline = errors.New("failed to write baseline")
+)
+
+// Baseline is a snapshot of known findings
+type Baseline struct {
+	Version   string         `json:"version"`
+	CreatedAt string         `json:"created_at"`
+	Tool      string         `json:"tool,omitempty"`
+	Findings  []BaselineItem `json:"findings"`
+}
+
+// BaselineItem is a fingerprint with the number of evidences having it.
+// Identical calls in the same file share the fingerprint.
+type BaselineItem struct {
+	Fingerprint
+	Count int `json:"count"`
+}
+
+// NewBaseline creates a baseline from the active and baseline suppressed findings
+func NewBaseline(tool common.ToolMetadata, sourcePath string, findings *common.CodeAnalysisFindings) *Baseline {
+	items := map[string]*BaselineItem{}
+
+	record := func(matchResults map[string][]common.EnrichedSignatureMatchResult, suppressedOnly bool) {
+		for _, signatureMatchResults := range matchResults {
+			for i := range signatureMatchResults {
+				result := &signatureMatchResults[i]
+				if suppressedOnly && (result.Suppression == nil || result.Suppression.Source != SuppressionSource) {
+					continue
+				}
+
+				for j := range result.MatchedConditions {
+					condition := &result.MatchedConditions[j]
+					for k := range condition.Evidences {
+						fingerprint := NewFingerprint(sourcePath, result, condition, result.Metadata(j, k))
+						if item, ok := items[fingerprint.Hash]; ok {
+							item.Count++
+						} else {
+							items[fingerprint.Hash] = &BaselineItem{Fingerprint: fingerprint, Count: 1}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	record(findings.SignatureWiseMatchResults, false)
+	record(findings.SuppressedMatchResults, true)
+
+	baselineItems := make([]BaselineItem, 0, len(items))
+	for _, item := range items {
+		baselineItems = append(baselineItems, *item)
+	}
+
+	sort.Slice(baselineItems, func(i, j int) bool {
+		a, b := baselineItems[i], baselineItems[j]
+		if a.SignatureID != b.SignatureID {
+			return a.SignatureID < b.SignatureID
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Hash < b.Hash
+	})
+
+	return &Baseline{
+		Version:   BaselineVersion,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Tool:      tool.Name + "@" + tool.Version,
+		Findings:  baselineItems,
+	}
+}
+
+// LoadBaseline reads a baseline file
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadBaseline, err)
+	}
+
+	var baseline Baseline
+	err = json.Unmarshal(data, &baseline)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrReadBaseline, path, err)
+	}
+
+	return &baseline, nil
+}
+
+// Write saves the baseline to a file
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteBaseline, err)
+	}
+
+	err = os.WriteFile(path, append(data, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteBaseline, err)
+	}
+
+	return nil
+}
+
+// Suppressor suppresses the evidences recorded in a baseline. Each baseline
+// item suppresses at most as many evidences as it was recorded for so that
+// new occurrences of an identical call are still reported.
+type Suppressor struct {
+	sourcePath string
+	remaining  map[string]int
+}
+
+// NewSuppressor creates a suppressor for a baseline. The source path must be
+// the scanned directory so that file paths match the ones in the baseline.
+func NewSuppressor(baseline *Baseline, sourcePath string) *Suppressor {
+	remaining := make(map[string]int, len(baseline.Findings))
+	for _, item := range baseline.Findings {
+		remaining[item.Hash] += max(item.Count, 1)
+	}
+
+	return &Suppressor{
+		sourcePath: sourcePath,
+		remaining:  remaining,
+	}
+}
+
+func (s *Suppressor) Name() string {
+	return "baseline"
+}
+
+func (s *Suppressor) Suppress(result *common.EnrichedSignatureMatchResult,
+	condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata,
+) (*common.Suppression, error) {
+	fingerprint := NewFingerprint(s.sourcePath, result, condition, metadata)
+	if s.remaining[fingerprint.Hash] <= 0 {
+		return nil, nil
+	}
+
+	s.remaining[fingerprint.Hash]--
+
+	return &common.Suppression{
+		Source: SuppressionSource,
+		Reason: "Known finding recorded in baseline",
+	}, nil
+}
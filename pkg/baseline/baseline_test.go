@@ -0,0 +1,117 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMatchResult(filePath, signatureId, value string, evidences int) common.EnrichedSignatureMatchResult {
+	return common.EnrichedSignatureMatchResult{
+		SignatureMatchResult: callgraph.SignatureMatchResult{
+			FilePath:            filePath,
+			MatchedSignature:    &callgraphv1.Signature{Id: signatureId},
+			MatchedLanguageCode: core.LanguageCodePython,
+			MatchedConditions: []callgraph.MatchedCondition{
+				{
+					Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+						Type:  "call",
+						Value: value,
+					},
+					Evidences: make([]callgraph.MatchedEvidence, evidences),
+				},
+			},
+		},
+	}
+}
+
+func TestNewFingerprint(t *testing.T) {
+	result := testMatchResult("/src/app/main.py", "openai.client", "openai.*", 1)
+	condition := &result.MatchedConditions[0]
+
+	fingerprint := NewFingerprint("/src", &result, condition, result.Metadata(0, 0))
+	assert.Equal(t, "openai.client", fingerprint.SignatureID)
+	assert.Equal(t, "app/main.py", fingerprint.FilePath)
+	assert.Equal(t, "call:openai.*", fingerprint.Condition)
+	assert.Len(t, fingerprint.Hash, 64)
+
+	// Fingerprint is independent of the scan root location
+	movedResult := testMatchResult("/tmp/checkout/app/main.py", "openai.client", "openai.*", 1)
+	movedFingerprint := NewFingerprint("/tmp/checkout", &movedResult,
+		&movedResult.MatchedConditions[0], movedResult.Metadata(0, 0))
+	assert.Equal(t, fingerprint.Hash, movedFingerprint.Hash)
+
+	otherResult := testMatchResult("/src/app/other.py", "openai.client", "openai.*", 1)
+	otherFingerprint := NewFingerprint("/src", &otherResult,
+		&otherResult.MatchedConditions[0], otherResult.Metadata(0, 0))
+	assert.NotEqual(t, fingerprint.Hash, otherFingerprint.Hash)
+
+	// Fingerprint depends on the matched code
+	snippetFingerprint := NewFingerprint("/src", &result, condition,
+		callgraph.EvidenceMetadata{CallerIdentifierContent: "openai.OpenAI()"})
+	assert.NotEqual(t, fingerprint.Hash, snippetFingerprint.Hash)
+}
+
+func TestNormalizeSnippet(t *testing.T) {
+	assert.Equal(t, "hashlib.md5( data )", normalizeSnippet("hashlib.md5(\n\t  data\n)"))
+}
+
+func TestBaselineWriteAndLoad(t *testing.T) {
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"openai.client": {testMatchResult("/src/main.py", "openai.client", "openai.*", 2)},
+		},
+		SuppressedMatchResults: map[string][]common.EnrichedSignatureMatchResult{},
+	}
+
+	baseline := NewBaseline(common.ToolMetadata{Name: "xbom", Version: "test"}, "/src", findings)
+	require.Len(t, baseline.Findings, 1)
+	assert.Equal(t, 2, baseline.Findings[0].Count)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, baseline.Write(path))
+
+	loaded, err := LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, BaselineVersion, loaded.Version)
+	assert.Equal(t, baseline.Findings, loaded.Findings)
+
+	_, err = LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, ErrReadBaseline)
+}
+
+func TestSuppressor(t *testing.T) {
+	baselineFindings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"openai.client": {testMatchResult("/src/main.py", "openai.client", "openai.*", 1)},
+		},
+	}
+
+	suppressor := NewSuppressor(NewBaseline(common.ToolMetadata{}, "/src", baselineFindings), "/src")
+
+	// A new occurrence of an identical call must be reported
+	result := testMatchResult("/src/main.py", "openai.client", "openai.*", 2)
+	condition := &result.MatchedConditions[0]
+
+	suppression, err := suppressor.Suppress(&result, condition, result.Metadata(0, 0))
+	require.NoError(t, err)
+	require.NotNil(t, suppression)
+	assert.Equal(t, SuppressionSource, suppression.Source)
+
+	suppression, err = suppressor.Suppress(&result, condition, result.Metadata(0, 1))
+	require.NoError(t, err)
+	assert.Nil(t, suppression)
+
+	// Findings in other files are not suppressed
+	otherResult := testMatchResult("/src/other.py", "openai.client", "openai.*", 1)
+	suppression, err = suppressor.Suppress(&otherResult, &otherResult.MatchedConditions[0],
+		otherResult.Metadata(0, 0))
+	require.NoError(t, err)
+	assert.Nil(t, suppression)
+}
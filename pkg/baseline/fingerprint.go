@@ -0,0 +1,60 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// Fingerprint identifies an evidence independent of its location within a file
+// so that it remains stable when unrelated code around it changes.
+type Fingerprint struct {
+	Hash        string `json:"fingerprint"`
+	SignatureID string `json:"signature_id"`
+	FilePath    string `json:"file_path"`
+	Condition   string `json:"condition"`
+}
+
+// NewFingerprint creates the fingerprint of an evidence from the signature ID, the
+// file path relative to the source path, the matched condition and a hash of the
+// normalized caller snippet.
+func NewFingerprint(sourcePath string, result *common.EnrichedSignatureMatchResult,
+	condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata,
+) Fingerprint {
+	filePath, ok := common.RelativeSourcePath(sourcePath, result.FilePath)
+	if !ok {
+		filePath = filepath.ToSlash(result.FilePath)
+	}
+
+	conditionString := ""
+	if condition.Condition != nil {
+		conditionString = condition.Condition.Type + ":" + condition.Condition.Value
+	}
+
+	snippetHash := sha256.Sum256([]byte(normalizeSnippet(metadata.CallerIdentifierContent)))
+
+	signatureId := result.MatchedSignature.GetId()
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		signatureId,
+		filePath,
+		conditionString,
+		hex.EncodeToString(snippetHash[:]),
+	}, "\x00")))
+
+	return Fingerprint{
+		Hash:        hex.EncodeToString(hash[:]),
+		SignatureID: signatureId,
+		FilePath:    filePath,
+		Condition:   conditionString,
+	}
+}
+
+// normalizeSnippet collapses all whitespace so that formatting
+// changes do not change the fingerprint
+func normalizeSnippet(snippet string) string {
+	return strings.Join(strings.Fields(snippet), " ")
+}
@@ -0,0 +1,440 @@
+package bomdiff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+var ErrLoadBOM = errors.New("failed to load BOM")
+
+type DiffConfig struct {
+	// OldSourcePath and NewSourcePath are the scanned directories of the old and
+	// new BOMs. Evidence locations are compared relative to these paths when
+	// set, so that BOMs generated from different checkouts can be compared.
+	OldSourcePath string
+	NewSourcePath string
+}
+
+// Occurrence is an evidence occurrence of a component
+type Occurrence struct {
+	Location string `json:"location"`
+	Line     int    `json:"line,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+	Context  string `json:"context,omitempty"`
+}
+
+// Component is a component found in only one of the BOMs
+type Component struct {
+	BOMRef      string   `json:"bom_ref"`
+	Name        string   `json:"name"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Occurrences int      `json:"occurrences"`
+	Files       []string `json:"files"`
+}
+
+// ComponentChange is a component found in both BOMs with different evidences
+type ComponentChange struct {
+	BOMRef             string       `json:"bom_ref"`
+	Name               string       `json:"name"`
+	Publisher          string       `json:"publisher,omitempty"`
+	AddedFiles         []string     `json:"added_files,omitempty"`
+	RemovedFiles       []string     `json:"removed_files,omitempty"`
+	AddedOccurrences   []Occurrence `json:"added_occurrences,omitempty"`
+	RemovedOccurrences []Occurrence `json:"removed_occurrences,omitempty"`
+}
+
+// Service is a service found in only one of the BOMs
+type Service struct {
+	BOMRef    string   `json:"bom_ref"`
+	Name      string   `json:"name"`
+	Provider  string   `json:"provider,omitempty"`
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// ServiceChange is a service found in both BOMs with different endpoints
+type ServiceChange struct {
+	BOMRef           string   `json:"bom_ref"`
+	Name             string   `json:"name"`
+	Provider         string   `json:"provider,omitempty"`
+	AddedEndpoints   []string `json:"added_endpoints,omitempty"`
+	RemovedEndpoints []string `json:"removed_endpoints,omitempty"`
+}
+
+// Result is the difference between two BOMs. All lists are sorted by BOM ref.
+type Result struct {
+	Added   []Component       `json:"added"`
+	Removed []Component       `json:"removed"`
+	Changed []ComponentChange `json:"changed"`
+
+	AddedServices   []Service       `json:"added_services"`
+	RemovedServices []Service       `json:"removed_services"`
+	ChangedServices []ServiceChange `json:"changed_services"`
+}
+
+func (r *Result) IsEmpty() bool {
+	return !r.hasComponentChanges() && !r.hasServiceChanges()
+}
+
+func (r *Result) hasComponentChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+func (r *Result) hasServiceChanges() bool {
+	return len(r.AddedServices) > 0 || len(r.RemovedServices) > 0 || len(r.ChangedServices) > 0
+}
+
+// LoadBOM reads a CycloneDX BOM in JSON format
+func LoadBOM(path string) (*cdx.BOM, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadBOM, err)
+	}
+
+	defer func() {
+		if err := fd.Close(); err != nil {
+			log.Errorf("Failed to close file %s: %v", path, err)
+		}
+	}()
+
+	bom := cdx.NewBOM()
+	err = cdx.NewBOMDecoder(fd, cdx.BOMFileFormatJSON).Decode(bom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLoadBOM, path, err)
+	}
+
+	return bom, nil
+}
+
+// Diff compares the components and services of two BOMs. Components and services
+// are identified by their BOM ref. Occurrences are compared by location and context
+// so that code moving within a file is not reported as a change. Services are
+// changed when their endpoints differ.
+func Diff(oldBom, newBom *cdx.BOM, config DiffConfig) *Result {
+	oldComponents := bomComponents(oldBom, config.OldSourcePath)
+	newComponents := bomComponents(newBom, config.NewSourcePath)
+
+	result := &Result{
+		Added:           []Component{},
+		Removed:         []Component{},
+		Changed:         []ComponentChange{},
+		AddedServices:   []Service{},
+		RemovedServices: []Service{},
+		ChangedServices: []ServiceChange{},
+	}
+
+	for _, key := range sortedKeys(newComponents) {
+		newComponent := newComponents[key]
+
+		oldComponent, ok := oldComponents[key]
+		if !ok {
+			result.Added = append(result.Added, newComponent.summary())
+			continue
+		}
+
+		addedOccurrences, removedOccurrences := diffOccurrences(oldComponent.occurrences, newComponent.occurrences)
+		change := ComponentChange{
+			BOMRef:             newComponent.bomRef,
+			Name:               newComponent.name,
+			Publisher:          newComponent.publisher,
+			AddedFiles:         subtractStrings(newComponent.files(), oldComponent.files()),
+			RemovedFiles:       subtractStrings(oldComponent.files(), newComponent.files()),
+			AddedOccurrences:   addedOccurrences,
+			RemovedOccurrences: removedOccurrences,
+		}
+
+		if len(change.AddedOccurrences) > 0 || len(change.RemovedOccurrences) > 0 {
+			result.Changed = append(result.Changed, change)
+		}
+	}
+
+	for _, key := range sortedKeys(oldComponents) {
+		if _, ok := newComponents[key]; !ok {
+			result.Removed = append(result.Removed, oldComponents[key].summary())
+		}
+	}
+
+	diffServices(result, bomServices(oldBom), bomServices(newBom))
+
+	return result
+}
+
+func diffServices(result *Result, oldServices, newServices map[string]*Service) {
+	for _, key := range sortedKeys(newServices) {
+		newService := newServices[key]
+
+		oldService, ok := oldServices[key]
+		if !ok {
+			result.AddedServices = append(result.AddedServices, *newService)
+			continue
+		}
+
+		change := ServiceChange{
+			BOMRef:           newService.BOMRef,
+			Name:             newService.Name,
+			Provider:         newService.Provider,
+			AddedEndpoints:   subtractStrings(newService.Endpoints, oldService.Endpoints),
+			RemovedEndpoints: subtractStrings(oldService.Endpoints, newService.Endpoints),
+		}
+
+		if len(change.AddedEndpoints) > 0 || len(change.RemovedEndpoints) > 0 {
+			result.ChangedServices = append(result.ChangedServices, change)
+		}
+	}
+
+	for _, key := range sortedKeys(oldServices) {
+		if _, ok := newServices[key]; !ok {
+			result.RemovedServices = append(result.RemovedServices, *oldServices[key])
+		}
+	}
+}
+
+type component struct {
+	bomRef      string
+	name        string
+	publisher   string
+	occurrences []Occurrence
+}
+
+func (c *component) files() []string {
+	files := map[string]bool{}
+	for _, occurrence := range c.occurrences {
+		files[occurrence.Location] = true
+	}
+
+	return sortedKeys(files)
+}
+
+func (c *component) summary() Component {
+	return Component{
+		BOMRef:      c.bomRef,
+		Name:        c.name,
+		Publisher:   c.publisher,
+		Occurrences: len(c.occurrences),
+		Files:       c.files(),
+	}
+}
+
+// bomComponents indexes the components of a BOM, including nested components, by BOM ref
+func bomComponents(bom *cdx.BOM, sourcePath string) map[string]*component {
+	components := map[string]*component{}
+	if bom.Components == nil {
+		return components
+	}
+
+	var visit func(cdxComponents []cdx.Component)
+	visit = func(cdxComponents []cdx.Component) {
+		for _, cdxComponent := range cdxComponents {
+			key := cdxComponent.BOMRef
+			if key == "" {
+				key = cdxComponent.Name
+			}
+
+			c := &component{
+				bomRef:      key,
+				name:        cdxComponent.Name,
+				publisher:   cdxComponent.Publisher,
+				occurrences: componentOccurrences(cdxComponent, sourcePath),
+			}
+
+			components[key] = c
+
+			if cdxComponent.Components != nil {
+				visit(*cdxComponent.Components)
+			}
+		}
+	}
+
+	visit(*bom.Components)
+
+	return components
+}
+
+// bomServices indexes the services of a BOM, including nested services, by BOM ref
+func bomServices(bom *cdx.BOM) map[string]*Service {
+	services := map[string]*Service{}
+	if bom.Services == nil {
+		return services
+	}
+
+	var visit func(cdxServices []cdx.Service)
+	visit = func(cdxServices []cdx.Service) {
+		for _, cdxService := range cdxServices {
+			key := cdxService.BOMRef
+			if key == "" {
+				key = cdxService.Name
+			}
+
+			service := &Service{
+				BOMRef: key,
+				Name:   cdxService.Name,
+			}
+
+			if cdxService.Provider != nil {
+				service.Provider = cdxService.Provider.Name
+			}
+
+			if cdxService.Endpoints != nil {
+				service.Endpoints = slices.Clone(*cdxService.Endpoints)
+				sort.Strings(service.Endpoints)
+			}
+
+			services[key] = service
+
+			if cdxService.Services != nil {
+				visit(*cdxService.Services)
+			}
+		}
+	}
+
+	visit(*bom.Services)
+
+	return services
+}
+
+func componentOccurrences(cdxComponent cdx.Component, sourcePath string) []Occurrence {
+	occurrences := []Occurrence{}
+	if cdxComponent.Evidence == nil || cdxComponent.Evidence.Occurrences == nil {
+		return occurrences
+	}
+
+	for _, cdxOccurrence := range *cdxComponent.Evidence.Occurrences {
+		location := cdxOccurrence.Location
+		if sourcePath != "" {
+			if relativePath, ok := common.RelativeSourcePath(sourcePath, location); ok {
+				location = relativePath
+			}
+		}
+
+		occurrence := Occurrence{
+			Location: location,
+			Context:  cdxOccurrence.AdditionalContext,
+		}
+
+		if cdxOccurrence.Line != nil {
+			occurrence.Line = *cdxOccurrence.Line
+		}
+
+		if cdxOccurrence.Offset != nil {
+			occurrence.Offset = *cdxOccurrence.Offset
+		}
+
+		occurrences = append(occurrences, occurrence)
+	}
+
+	sortOccurrences(occurrences)
+
+	return occurrences
+}
+
+// diffOccurrences compares occurrences grouped by location and context. Within a
+// group, occurrences at the same position are matched first and any remaining
+// occurrences are matched in order, as they are most likely moved code. Only
+// the unmatched occurrences are reported as added or removed.
+func diffOccurrences(oldOccurrences, newOccurrences []Occurrence) (added, removed []Occurrence) {
+	type groupKey struct {
+		location string
+		context  string
+	}
+
+	groups := map[groupKey][2][]Occurrence{}
+	for _, occurrence := range oldOccurrences {
+		key := groupKey{occurrence.Location, occurrence.Context}
+		group := groups[key]
+		group[0] = append(group[0], occurrence)
+		groups[key] = group
+	}
+
+	for _, occurrence := range newOccurrences {
+		key := groupKey{occurrence.Location, occurrence.Context}
+		group := groups[key]
+		group[1] = append(group[1], occurrence)
+		groups[key] = group
+	}
+
+	for _, group := range groups {
+		oldUnmatched := subtractOccurrences(group[0], group[1])
+		newUnmatched := subtractOccurrences(group[1], group[0])
+
+		moved := min(len(oldUnmatched), len(newUnmatched))
+		removed = append(removed, oldUnmatched[moved:]...)
+		added = append(added, newUnmatched[moved:]...)
+	}
+
+	sortOccurrences(added)
+	sortOccurrences(removed)
+
+	return added, removed
+}
+
+// subtractOccurrences returns the occurrences of a without an occurrence at the same position in b
+func subtractOccurrences(a, b []Occurrence) []Occurrence {
+	positions := map[Occurrence]int{}
+	for _, occurrence := range b {
+		positions[occurrence]++
+	}
+
+	result := []Occurrence{}
+	for _, occurrence := range a {
+		if positions[occurrence] > 0 {
+			positions[occurrence]--
+			continue
+		}
+
+		result = append(result, occurrence)
+	}
+
+	return result
+}
+
+func subtractStrings(a, b []string) []string {
+	files := map[string]bool{}
+	for _, file := range b {
+		files[file] = true
+	}
+
+	var result []string
+	for _, file := range a {
+		if !files[file] {
+			result = append(result, file)
+		}
+	}
+
+	return result
+}
+
+func sortOccurrences(occurrences []Occurrence) {
+	sort.Slice(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+
+		return a.Context < b.Context
+	})
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
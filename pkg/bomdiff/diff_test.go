@@ -0,0 +1,265 @@
+package bomdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestComponent(bomRef string, occurrences ...cdx.EvidenceOccurrence) cdx.Component {
+	return cdx.Component{
+		BOMRef:    bomRef,
+		Name:      bomRef + " name",
+		Publisher: "vendor",
+		Type:      cdx.ComponentTypeLibrary,
+		Evidence: &cdx.Evidence{
+			Occurrences: utils.PtrTo(occurrences),
+		},
+	}
+}
+
+func newTestOccurrence(location string, line int, context string) cdx.EvidenceOccurrence {
+	return cdx.EvidenceOccurrence{
+		Location:          location,
+		Line:              utils.PtrTo(line),
+		Offset:            utils.PtrTo(1),
+		AdditionalContext: context,
+	}
+}
+
+func newTestBOM(components ...cdx.Component) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.Components = utils.PtrTo(components)
+
+	return bom
+}
+
+func TestDiff(t *testing.T) {
+	oldBom := newTestBOM(
+		newTestComponent("openai.chat",
+			newTestOccurrence("/old/app/main.py", 10, "openai.chat"),
+		),
+		newTestComponent("golang.crypto.aes",
+			newTestOccurrence("/old/app/crypto.go", 5, "crypto/aes/NewCipher"),
+		),
+		newTestComponent("golang.crypto.hash",
+			newTestOccurrence("/old/app/hash.go", 10, "crypto/sha256/Sum256"),
+			newTestOccurrence("/old/app/legacy.go", 3, "crypto/md5/Sum"),
+		),
+	)
+
+	newBom := newTestBOM(
+		newTestComponent("openai.chat",
+			// Moved within the file
+			newTestOccurrence("/new/app/main.py", 12, "openai.chat"),
+		),
+		newTestComponent("anthropic.messages",
+			newTestOccurrence("/new/app/agent.py", 1, "anthropic.messages"),
+			newTestOccurrence("/new/app/agent.py", 9, "anthropic.messages"),
+		),
+		newTestComponent("golang.crypto.hash",
+			newTestOccurrence("/new/app/hash.go", 10, "crypto/sha256/Sum256"),
+			newTestOccurrence("/new/app/hash.go", 20, "crypto/sha256/Sum256"),
+			newTestOccurrence("/new/app/sum.go", 7, "crypto/sha256/Sum256"),
+		),
+	)
+
+	result := Diff(oldBom, newBom, DiffConfig{
+		OldSourcePath: "/old/app",
+		NewSourcePath: "/new/app",
+	})
+
+	assert.Equal(t, []Component{
+		{
+			BOMRef:      "anthropic.messages",
+			Name:        "anthropic.messages name",
+			Publisher:   "vendor",
+			Occurrences: 2,
+			Files:       []string{"agent.py"},
+		},
+	}, result.Added)
+
+	assert.Equal(t, []Component{
+		{
+			BOMRef:      "golang.crypto.aes",
+			Name:        "golang.crypto.aes name",
+			Publisher:   "vendor",
+			Occurrences: 1,
+			Files:       []string{"crypto.go"},
+		},
+	}, result.Removed)
+
+	require.Len(t, result.Changed, 1)
+
+	change := result.Changed[0]
+	assert.Equal(t, "golang.crypto.hash", change.BOMRef)
+	assert.Equal(t, []string{"sum.go"}, change.AddedFiles)
+	assert.Equal(t, []string{"legacy.go"}, change.RemovedFiles)
+	assert.Equal(t, []Occurrence{
+		{Location: "hash.go", Line: 20, Offset: 1, Context: "crypto/sha256/Sum256"},
+		{Location: "sum.go", Line: 7, Offset: 1, Context: "crypto/sha256/Sum256"},
+	}, change.AddedOccurrences)
+	assert.Equal(t, []Occurrence{
+		{Location: "legacy.go", Line: 3, Offset: 1, Context: "crypto/md5/Sum"},
+	}, change.RemovedOccurrences)
+}
+
+func newTestService(bomRef string, endpoints ...string) cdx.Service {
+	return cdx.Service{
+		BOMRef:    bomRef,
+		Name:      bomRef + " name",
+		Provider:  &cdx.OrganizationalEntity{Name: "vendor"},
+		Endpoints: utils.PtrTo(endpoints),
+	}
+}
+
+func TestDiffServices(t *testing.T) {
+	oldBom := newTestBOM()
+	oldBom.Services = utils.PtrTo([]cdx.Service{
+		newTestService("service:openai.chat", "https://api.openai.com/v1/chat"),
+		newTestService("service:aws.s3", "https://s3.amazonaws.com"),
+		newTestService("service:stripe.charges", "https://api.stripe.com"),
+	})
+
+	newBom := newTestBOM()
+	newBom.Services = utils.PtrTo([]cdx.Service{
+		newTestService("service:stripe.charges", "https://api.stripe.com"),
+		newTestService("service:openai.chat", "https://api.openai.com/v1/responses"),
+		newTestService("service:anthropic.messages", "https://api.anthropic.com"),
+	})
+
+	result := Diff(oldBom, newBom, DiffConfig{})
+	assert.False(t, result.IsEmpty())
+	assert.Empty(t, result.Added)
+
+	assert.Equal(t, []Service{
+		{
+			BOMRef:    "service:anthropic.messages",
+			Name:      "service:anthropic.messages name",
+			Provider:  "vendor",
+			Endpoints: []string{"https://api.anthropic.com"},
+		},
+	}, result.AddedServices)
+
+	assert.Equal(t, []Service{
+		{
+			BOMRef:    "service:aws.s3",
+			Name:      "service:aws.s3 name",
+			Provider:  "vendor",
+			Endpoints: []string{"https://s3.amazonaws.com"},
+		},
+	}, result.RemovedServices)
+
+	assert.Equal(t, []ServiceChange{
+		{
+			BOMRef:           "service:openai.chat",
+			Name:             "service:openai.chat name",
+			Provider:         "vendor",
+			AddedEndpoints:   []string{"https://api.openai.com/v1/responses"},
+			RemovedEndpoints: []string{"https://api.openai.com/v1/chat"},
+		},
+	}, result.ChangedServices)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, result, FormatMarkdown))
+	assert.Contains(t, buf.String(), "| Services | 1 | 1 | 1 |")
+	assert.Contains(t, buf.String(), "## Added Services")
+	assert.Contains(t, buf.String(), "## Removed Services")
+	assert.Contains(t, buf.String(), "| `service:openai.chat` | service:openai.chat name | `https://api.openai.com/v1/responses` | `https://api.openai.com/v1/chat` |")
+	assert.NotContains(t, buf.String(), "## Added Components")
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, result, FormatTable))
+	assert.Contains(t, buf.String(), "Service Changes")
+	assert.NotContains(t, buf.String(), "Component Changes")
+}
+
+func TestDiffWithoutSourcePathComparesLocations(t *testing.T) {
+	oldBom := newTestBOM(newTestComponent("openai.chat", newTestOccurrence("/old/main.py", 1, "openai.chat")))
+	newBom := newTestBOM(newTestComponent("openai.chat", newTestOccurrence("/new/main.py", 1, "openai.chat")))
+
+	result := Diff(oldBom, newBom, DiffConfig{})
+	require.Len(t, result.Changed, 1)
+	assert.Equal(t, []string{"/new/main.py"}, result.Changed[0].AddedFiles)
+	assert.Equal(t, []string{"/old/main.py"}, result.Changed[0].RemovedFiles)
+}
+
+func TestDiffIdenticalBOMs(t *testing.T) {
+	bom := newTestBOM(newTestComponent("openai.chat", newTestOccurrence("main.py", 1, "openai.chat")))
+
+	result := Diff(bom, bom, DiffConfig{})
+	assert.True(t, result.IsEmpty())
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, result, FormatTable))
+	assert.Contains(t, buf.String(), "No differences found")
+}
+
+func TestLoadBOM(t *testing.T) {
+	bomPath := filepath.Join(t.TempDir(), "bom.cdx.json")
+
+	fd, err := os.Create(bomPath)
+	require.NoError(t, err)
+
+	bom := newTestBOM(newTestComponent("openai.chat", newTestOccurrence("main.py", 1, "openai.chat")))
+	require.NoError(t, cdx.NewBOMEncoder(fd, cdx.BOMFileFormatJSON).Encode(bom))
+	require.NoError(t, fd.Close())
+
+	loadedBom, err := LoadBOM(bomPath)
+	require.NoError(t, err)
+	require.NotNil(t, loadedBom.Components)
+	assert.Len(t, *loadedBom.Components, 1)
+
+	_, err = LoadBOM(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, ErrLoadBOM)
+}
+
+func TestRender(t *testing.T) {
+	oldBom := newTestBOM(newTestComponent("openai.chat", newTestOccurrence("main.py", 1, "openai.chat")))
+	newBom := newTestBOM(
+		newTestComponent("openai.chat",
+			newTestOccurrence("main.py", 1, "openai.chat"),
+			newTestOccurrence("main.py", 5, "openai.chat"),
+		),
+		newTestComponent("anthropic.messages", newTestOccurrence("agent.py", 1, "anthropic.messages")),
+	)
+
+	result := Diff(oldBom, newBom, DiffConfig{})
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, result, FormatTable))
+		assert.Contains(t, buf.String(), "anthropic.messages")
+		assert.Contains(t, buf.String(), "main.py:5")
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, result, FormatMarkdown))
+		assert.Contains(t, buf.String(), "## Added Components")
+		assert.Contains(t, buf.String(), "## Changed Components")
+		assert.NotContains(t, buf.String(), "## Removed Components")
+		assert.Contains(t, buf.String(), "| + | `main.py:5` | openai.chat |")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, result, FormatJSON))
+
+		var decoded Result
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, *result, decoded)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		err := Render(&bytes.Buffer{}, result, "xml")
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
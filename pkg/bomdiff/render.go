@@ -0,0 +1,289 @@
+package bomdiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const (
+	FormatTable    = "table"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Formats returns the supported output formats
+func Formats() []string {
+	return []string{FormatTable, FormatMarkdown, FormatJSON}
+}
+
+// Render writes the diff result in the given format
+func Render(w io.Writer, result *Result, format string) error {
+	switch format {
+	case FormatTable:
+		return renderTable(w, result)
+	case FormatMarkdown:
+		return renderMarkdown(w, result)
+	case FormatJSON:
+		return renderJSON(w, result)
+	default:
+		return fmt.Errorf("%w: %s (supported: %s)", ErrUnsupportedFormat,
+			format, strings.Join(Formats(), ", "))
+	}
+}
+
+func renderTable(w io.Writer, result *Result) error {
+	if result.IsEmpty() {
+		_, err := fmt.Fprintln(w, "✅ No differences found")
+		return err
+	}
+
+	if result.hasComponentChanges() {
+		renderComponentTables(w, result)
+	}
+
+	if result.hasServiceChanges() {
+		renderServiceTable(w, result)
+	}
+
+	return nil
+}
+
+func renderComponentTables(w io.Writer, result *Result) {
+	componentTable := table.NewWriter()
+	componentTable.SetOutputMirror(w)
+	componentTable.SetStyle(table.StyleRounded)
+	componentTable.SetTitle("🔀 Component Changes")
+	componentTable.AppendHeader(table.Row{"Change", "Component", "Name", "Details"})
+
+	for _, c := range result.Added {
+		componentTable.AppendRow(table.Row{"+ added", c.BOMRef, c.Name, componentDetails(c)})
+	}
+
+	for _, c := range result.Removed {
+		componentTable.AppendRow(table.Row{"- removed", c.BOMRef, c.Name, componentDetails(c)})
+	}
+
+	for _, c := range result.Changed {
+		componentTable.AppendRow(table.Row{"~ changed", c.BOMRef, c.Name, changeDetails(c)})
+	}
+
+	componentTable.Render()
+
+	if len(result.Changed) == 0 {
+		return
+	}
+
+	occurrenceTable := table.NewWriter()
+	occurrenceTable.SetOutputMirror(w)
+	occurrenceTable.SetStyle(table.StyleRounded)
+	occurrenceTable.SetTitle("📍 Changed Occurrences")
+	occurrenceTable.AppendHeader(table.Row{"Component", "Change", "Location", "Context"})
+	occurrenceTable.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AutoMerge: true},
+	})
+
+	for _, c := range result.Changed {
+		for _, occurrence := range c.AddedOccurrences {
+			occurrenceTable.AppendRow(table.Row{c.BOMRef, "+", occurrenceLocation(occurrence), occurrence.Context})
+		}
+
+		for _, occurrence := range c.RemovedOccurrences {
+			occurrenceTable.AppendRow(table.Row{c.BOMRef, "-", occurrenceLocation(occurrence), occurrence.Context})
+		}
+	}
+
+	occurrenceTable.Render()
+}
+
+func renderServiceTable(w io.Writer, result *Result) {
+	serviceTable := table.NewWriter()
+	serviceTable.SetOutputMirror(w)
+	serviceTable.SetStyle(table.StyleRounded)
+	serviceTable.SetTitle("🌐 Service Changes")
+	serviceTable.AppendHeader(table.Row{"Change", "Service", "Name", "Details"})
+
+	for _, s := range result.AddedServices {
+		serviceTable.AppendRow(table.Row{"+ added", s.BOMRef, s.Name, strings.Join(s.Endpoints, "\n")})
+	}
+
+	for _, s := range result.RemovedServices {
+		serviceTable.AppendRow(table.Row{"- removed", s.BOMRef, s.Name, strings.Join(s.Endpoints, "\n")})
+	}
+
+	for _, s := range result.ChangedServices {
+		serviceTable.AppendRow(table.Row{"~ changed", s.BOMRef, s.Name, serviceChangeDetails(s)})
+	}
+
+	serviceTable.Render()
+}
+
+func renderMarkdown(w io.Writer, result *Result) error {
+	sb := strings.Builder{}
+	sb.WriteString("# BOM Diff\n\n")
+
+	if result.IsEmpty() {
+		sb.WriteString("No differences found.\n")
+
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	fmt.Fprintf(&sb, "| | Added | Removed | Changed |\n")
+	fmt.Fprintf(&sb, "|-|-------|---------|---------|\n")
+	fmt.Fprintf(&sb, "| Components | %d | %d | %d |\n", len(result.Added), len(result.Removed), len(result.Changed))
+	fmt.Fprintf(&sb, "| Services | %d | %d | %d |\n\n",
+		len(result.AddedServices), len(result.RemovedServices), len(result.ChangedServices))
+
+	writeComponents := func(title string, components []Component) {
+		if len(components) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&sb, "## %s\n\n", title)
+		sb.WriteString("| Component | Name | Publisher | Occurrences | Files |\n")
+		sb.WriteString("|-----------|------|-----------|-------------|-------|\n")
+
+		for _, c := range components {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %d | %s |\n", c.BOMRef, markdownEscape(c.Name),
+				markdownEscape(c.Publisher), c.Occurrences, markdownCodeList(c.Files))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	writeComponents("Added Components", result.Added)
+	writeComponents("Removed Components", result.Removed)
+
+	if len(result.Changed) > 0 {
+		sb.WriteString("## Changed Components\n\n")
+
+		for _, c := range result.Changed {
+			fmt.Fprintf(&sb, "### `%s` %s\n\n", c.BOMRef, markdownEscape(c.Name))
+
+			if len(c.AddedFiles) > 0 {
+				fmt.Fprintf(&sb, "New files: %s\n\n", markdownCodeList(c.AddedFiles))
+			}
+
+			if len(c.RemovedFiles) > 0 {
+				fmt.Fprintf(&sb, "Removed files: %s\n\n", markdownCodeList(c.RemovedFiles))
+			}
+
+			sb.WriteString("| Change | Location | Context |\n")
+			sb.WriteString("|--------|----------|---------|\n")
+
+			for _, occurrence := range c.AddedOccurrences {
+				fmt.Fprintf(&sb, "| + | `%s` | %s |\n", occurrenceLocation(occurrence), markdownEscape(occurrence.Context))
+			}
+
+			for _, occurrence := range c.RemovedOccurrences {
+				fmt.Fprintf(&sb, "| - | `%s` | %s |\n", occurrenceLocation(occurrence), markdownEscape(occurrence.Context))
+			}
+
+			sb.WriteString("\n")
+		}
+	}
+
+	writeServices := func(title string, services []Service) {
+		if len(services) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&sb, "## %s\n\n", title)
+		sb.WriteString("| Service | Name | Provider | Endpoints |\n")
+		sb.WriteString("|---------|------|----------|-----------|\n")
+
+		for _, s := range services {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", s.BOMRef, markdownEscape(s.Name),
+				markdownEscape(s.Provider), markdownCodeList(s.Endpoints))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	writeServices("Added Services", result.AddedServices)
+	writeServices("Removed Services", result.RemovedServices)
+
+	if len(result.ChangedServices) > 0 {
+		sb.WriteString("## Changed Services\n\n")
+		sb.WriteString("| Service | Name | New Endpoints | Removed Endpoints |\n")
+		sb.WriteString("|---------|------|---------------|-------------------|\n")
+
+		for _, s := range result.ChangedServices {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", s.BOMRef, markdownEscape(s.Name),
+				markdownCodeList(s.AddedEndpoints), markdownCodeList(s.RemovedEndpoints))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func renderJSON(w io.Writer, result *Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(result)
+}
+
+func componentDetails(c Component) string {
+	return fmt.Sprintf("%d occurrences in %d files", c.Occurrences, len(c.Files))
+}
+
+func changeDetails(c ComponentChange) string {
+	details := []string{
+		fmt.Sprintf("+%d / -%d occurrences", len(c.AddedOccurrences), len(c.RemovedOccurrences)),
+	}
+
+	if len(c.AddedFiles) > 0 {
+		details = append(details, fmt.Sprintf("new files: %s", strings.Join(c.AddedFiles, ", ")))
+	}
+
+	if len(c.RemovedFiles) > 0 {
+		details = append(details, fmt.Sprintf("removed files: %s", strings.Join(c.RemovedFiles, ", ")))
+	}
+
+	return strings.Join(details, "\n")
+}
+
+func serviceChangeDetails(s ServiceChange) string {
+	details := []string{}
+	if len(s.AddedEndpoints) > 0 {
+		details = append(details, fmt.Sprintf("new endpoints: %s", strings.Join(s.AddedEndpoints, ", ")))
+	}
+
+	if len(s.RemovedEndpoints) > 0 {
+		details = append(details, fmt.Sprintf("removed endpoints: %s", strings.Join(s.RemovedEndpoints, ", ")))
+	}
+
+	return strings.Join(details, "\n")
+}
+
+func occurrenceLocation(occurrence Occurrence) string {
+	if occurrence.Line > 0 {
+		return fmt.Sprintf("%s:%d", occurrence.Location, occurrence.Line)
+	}
+
+	return occurrence.Location
+}
+
+func markdownCodeList(files []string) string {
+	quoted := make([]string, 0, len(files))
+	for _, file := range files {
+		quoted = append(quoted, fmt.Sprintf("`%s`", file))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
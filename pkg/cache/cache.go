@@ -0,0 +1,369 @@
+// Package cache implements a persistent cache of the match results of source
+// files so that re-scans analyse only the files modified since the last scan.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+	"google.golang.org/protobuf/proto"
+)
+
+// CacheVersion is the version of the cache entry format. Entries of other
+// versions are never loaded.
+const CacheVersion = "1"
+
+// DefaultMaxAge is the time after which unused entries are pruned
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+var (
+	ErrCreateCache = errors.New("failed to create cache")
+	ErrLoadEntry   = errors.New("failed to load cache entry")
+	ErrStoreEntry  = errors.New("failed to store cache entry")
+	ErrPruneCache  = errors.New("failed to prune cache")
+)
+
+type FileCacheConfig struct {
+	// Dir is the directory of the cache entries, created when missing
+	Dir string
+
+	// Tool invalidates entries created by other versions of the tool
+	Tool common.ToolMetadata
+
+	// Signatures invalidate entries created with other signatures. Restored
+	// results refer to these signatures.
+	Signatures []*callgraphv1.Signature
+
+	// MaxAge is the time after which entries not loaded or stored are removed
+	// by Prune. It defaults to DefaultMaxAge when zero.
+	MaxAge time.Duration
+}
+
+// FileCache stores the match results of a source file in a file named by a key
+// derived from the tool version, the signatures, the file path and the file
+// content. The path is part of the key because the namespaces of evidences
+// depend on it. Entries of modified files and of other tool versions or signatures
+// are never loaded again, they are removed by Prune once unused for MaxAge. It is
+// safe for concurrent use.
+type FileCache struct {
+	dir        string
+	digest     []byte
+	signatures map[string]*callgraphv1.Signature
+	maxAge     time.Duration
+}
+
+type cacheEntry struct {
+	FilePath string        `json:"file_path"`
+	Results  []cacheResult `json:"results"`
+}
+
+type cacheResult struct {
+	SignatureID string           `json:"signature_id"`
+	Language    string           `json:"language"`
+	Conditions  []cacheCondition `json:"conditions"`
+}
+
+type cacheCondition struct {
+	Type      string                       `json:"type"`
+	Value     string                       `json:"value"`
+	Evidences []callgraph.EvidenceMetadata `json:"evidences"`
+}
+
+func NewFileCache(config FileCacheConfig) (*FileCache, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("%w: cache directory is required", ErrCreateCache)
+	}
+
+	err := os.MkdirAll(config.Dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateCache, err)
+	}
+
+	digest, err := signaturesDigest(config.Tool, config.Signatures)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateCache, err)
+	}
+
+	signatures := make(map[string]*callgraphv1.Signature, len(config.Signatures))
+	for _, signature := range config.Signatures {
+		signatures[signature.GetId()] = signature
+	}
+
+	maxAge := config.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	return &FileCache{
+		dir:        config.Dir,
+		digest:     digest,
+		signatures: signatures,
+		maxAge:     maxAge,
+	}, nil
+}
+
+// Load returns the cached match results of a file with the content. The evidences
+// of the results have no tree nodes, their metadata is available only through
+// the EvidenceMetadata of the results.
+func (c *FileCache) Load(filePath string, content []byte) ([]common.EnrichedSignatureMatchResult, bool, error) {
+	entryPath := c.entryPath(filePath, content)
+
+	data, err := os.ReadFile(entryPath)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrLoadEntry, err)
+	}
+
+	var entry cacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s: %w", ErrLoadEntry, entryPath, err)
+	}
+
+	// Guard against key collisions
+	if entry.FilePath != filePath {
+		return nil, false, nil
+	}
+
+	results := make([]common.EnrichedSignatureMatchResult, 0, len(entry.Results))
+	for _, cachedResult := range entry.Results {
+		signature, ok := c.signatures[cachedResult.SignatureID]
+		if !ok {
+			log.Warnf("Ignoring cache entry %s of unknown signature %s", entryPath, cachedResult.SignatureID)
+			return nil, false, nil
+		}
+
+		results = append(results, restoreResult(filePath, signature, cachedResult))
+	}
+
+	// Entries in use are kept by Prune
+	now := time.Now()
+	if err := os.Chtimes(entryPath, now, now); err != nil {
+		log.Debugf("Failed to update the modification time of cache entry %s: %v", entryPath, err)
+	}
+
+	return results, true, nil
+}
+
+// Prune removes the entries not loaded or stored for longer than MaxAge, along with
+// files left over by interrupted stores. It returns the number of removed entries.
+func (c *FileCache) Prune() (int, error) {
+	expiry := time.Now().Add(-c.maxAge)
+	removed := 0
+
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasPrefix(name, ".entry-")) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(expiry) {
+			return nil
+		}
+
+		// Entries may be removed concurrently by another scan
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		if strings.HasSuffix(name, ".json") {
+			removed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("%w: %w", ErrPruneCache, err)
+	}
+
+	return removed, nil
+}
+
+// Store saves the match results of a file with the content, including files without
+// any match. The evidence metadata of the results must be resolved.
+func (c *FileCache) Store(filePath string, content []byte, results []common.EnrichedSignatureMatchResult) error {
+	entry := cacheEntry{
+		FilePath: filePath,
+		Results:  make([]cacheResult, 0, len(results)),
+	}
+
+	for i := range results {
+		result := &results[i]
+
+		cachedResult := cacheResult{
+			SignatureID: result.MatchedSignature.GetId(),
+			Language:    string(result.MatchedLanguageCode),
+			Conditions:  make([]cacheCondition, 0, len(result.MatchedConditions)),
+		}
+
+		for j, condition := range result.MatchedConditions {
+			cachedCondition := cacheCondition{
+				Type:      condition.Condition.GetType(),
+				Value:     condition.Condition.GetValue(),
+				Evidences: make([]callgraph.EvidenceMetadata, 0, len(condition.Evidences)),
+			}
+
+			for k := range condition.Evidences {
+				cachedCondition.Evidences = append(cachedCondition.Evidences, result.Metadata(j, k))
+			}
+
+			cachedResult.Conditions = append(cachedResult.Conditions, cachedCondition)
+		}
+
+		entry.Results = append(entry.Results, cachedResult)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStoreEntry, err)
+	}
+
+	entryPath := c.entryPath(filePath, content)
+	err = os.MkdirAll(filepath.Dir(entryPath), 0o755)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStoreEntry, err)
+	}
+
+	// Entries are renamed into place so that a concurrent scan
+	// never loads a partially written entry
+	tempFile, err := os.CreateTemp(filepath.Dir(entryPath), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStoreEntry, err)
+	}
+
+	_, err = tempFile.Write(data)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tempFile.Name(), entryPath)
+	}
+
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return fmt.Errorf("%w: %w", ErrStoreEntry, err)
+	}
+
+	return nil
+}
+
+// entryPath returns the path of the entry of a file with the content,
+// sharded by the first byte of the key to keep directories small
+func (c *FileCache) entryPath(filePath string, content []byte) string {
+	contentHash := sha256.Sum256(content)
+
+	hash := sha256.New()
+	hash.Write(c.digest)
+	hash.Write([]byte(filePath))
+	hash.Write([]byte{0})
+	hash.Write(contentHash[:])
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// signaturesDigest hashes the cache version, the tool and the signatures
+// ordered by ID so that the digest does not depend on the loading order
+func signaturesDigest(tool common.ToolMetadata, signatures []*callgraphv1.Signature) ([]byte, error) {
+	sortedSignatures := make([]*callgraphv1.Signature, len(signatures))
+	copy(sortedSignatures, signatures)
+
+	sort.Slice(sortedSignatures, func(i, j int) bool {
+		return sortedSignatures[i].GetId() < sortedSignatures[j].GetId()
+	})
+
+	hash := sha256.New()
+	for _, part := range []string{CacheVersion, tool.Name, tool.Version} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	marshalOptions := proto.MarshalOptions{Deterministic: true}
+	for _, signature := range sortedSignatures {
+		data, err := marshalOptions.Marshal(signature)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal signature %s: %w", signature.GetId(), err)
+		}
+
+		signatureHash := sha256.Sum256(data)
+		hash.Write(signatureHash[:])
+	}
+
+	return hash.Sum(nil), nil
+}
+
+func restoreResult(filePath string, signature *callgraphv1.Signature,
+	cachedResult cacheResult,
+) common.EnrichedSignatureMatchResult {
+	result := common.EnrichedSignatureMatchResult{
+		SignatureMatchResult: callgraph.SignatureMatchResult{
+			FilePath:            filePath,
+			MatchedSignature:    signature,
+			MatchedLanguageCode: core.LanguageCode(cachedResult.Language),
+			MatchedConditions:   make([]callgraph.MatchedCondition, 0, len(cachedResult.Conditions)),
+		},
+		EvidenceMetadata: make([][]callgraph.EvidenceMetadata, 0, len(cachedResult.Conditions)),
+	}
+
+	for _, cachedCondition := range cachedResult.Conditions {
+		result.MatchedConditions = append(result.MatchedConditions, callgraph.MatchedCondition{
+			Condition: signatureCondition(signature, cachedResult.Language, cachedCondition),
+			Evidences: make([]callgraph.MatchedEvidence, len(cachedCondition.Evidences)),
+		})
+		result.EvidenceMetadata = append(result.EvidenceMetadata, cachedCondition.Evidences)
+	}
+
+	// Evidences are created from the metadata
+	result.Detach()
+
+	return result
+}
+
+// signatureCondition finds the matched condition in the signature, creating
+// an identical condition when the signature has no such condition
+func signatureCondition(signature *callgraphv1.Signature, language string,
+	cachedCondition cacheCondition,
+) *callgraphv1.Signature_LanguageMatcher_SignatureCondition {
+	for _, condition := range signature.GetLanguages()[language].GetConditions() {
+		if condition.GetType() == cachedCondition.Type && condition.GetValue() == cachedCondition.Value {
+			return condition
+		}
+	}
+
+	return &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+		Type:  cachedCondition.Type,
+		Value: cachedCondition.Value,
+	}
+}
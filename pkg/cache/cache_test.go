@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSignature(value string) *callgraphv1.Signature {
+	return &callgraphv1.Signature{
+		Id: "openai.client",
+		Languages: map[string]*callgraphv1.Signature_LanguageMatcher{
+			"python": {
+				Match: "any",
+				Conditions: []*callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+					{Type: "call", Value: value},
+				},
+			},
+		},
+	}
+}
+
+func testCache(t *testing.T, dir, version string, signature *callgraphv1.Signature) *FileCache {
+	fileCache, err := NewFileCache(FileCacheConfig{
+		Dir:        dir,
+		Tool:       common.ToolMetadata{Name: "xbom", Version: version},
+		Signatures: []*callgraphv1.Signature{signature},
+	})
+	require.NoError(t, err)
+
+	return fileCache
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	signature := testSignature("openai.*")
+	fileCache := testCache(t, dir, "v1.0.0", signature)
+
+	metadata := callgraph.EvidenceMetadata{
+		CallerNamespace:         "/src/main.py",
+		CalleeNamespace:         "openai.OpenAI",
+		CallerIdentifierContent: "openai.OpenAI()",
+		CallerIdentifierMetadata: &callgraph.TreeNodeMetadata{
+			StartLine: 4, EndLine: 4, StartColumn: 9, EndColumn: 24,
+		},
+	}
+
+	result := common.EnrichedSignatureMatchResult{
+		SignatureMatchResult: callgraph.SignatureMatchResult{
+			FilePath:            "/src/main.py",
+			MatchedSignature:    signature,
+			MatchedLanguageCode: core.LanguageCodePython,
+			MatchedConditions: []callgraph.MatchedCondition{
+				{
+					Condition: signature.Languages["python"].Conditions[0],
+					Evidences: []callgraph.MatchedEvidence{{}},
+				},
+			},
+		},
+		EvidenceMetadata: [][]callgraph.EvidenceMetadata{{metadata}},
+	}
+
+	content := []byte("import openai\n")
+
+	_, found, err := fileCache.Load("/src/main.py", content)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, fileCache.Store("/src/main.py", content, []common.EnrichedSignatureMatchResult{result}))
+	require.NoError(t, fileCache.Store("/src/empty.py", content, nil))
+
+	results, found, err := fileCache.Load("/src/main.py", content)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, "/src/main.py", results[0].FilePath)
+	assert.Same(t, signature, results[0].MatchedSignature)
+	assert.Equal(t, core.LanguageCodePython, results[0].MatchedLanguageCode)
+	require.Len(t, results[0].MatchedConditions, 1)
+	assert.Same(t, signature.Languages["python"].Conditions[0], results[0].MatchedConditions[0].Condition)
+	require.Len(t, results[0].MatchedConditions[0].Evidences, 1)
+	assert.Equal(t, metadata, results[0].Metadata(0, 0))
+
+	// Files without matches are cached too
+	results, found, err = fileCache.Load("/src/empty.py", content)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, results)
+
+	t.Run("modified content", func(t *testing.T) {
+		_, found, err := fileCache.Load("/src/main.py", []byte("import openai\nimport os\n"))
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("other tool version", func(t *testing.T) {
+		_, found, err := testCache(t, dir, "v1.1.0", signature).Load("/src/main.py", content)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("modified signatures", func(t *testing.T) {
+		_, found, err := testCache(t, dir, "v1.0.0", testSignature("openai.OpenAI")).Load("/src/main.py", content)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestFileCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	signature := testSignature("openai.*")
+	fileCache := testCache(t, dir, "v1.0.0", signature)
+
+	for _, name := range []string{"used.py", "unused.py"} {
+		require.NoError(t, fileCache.Store(name, []byte(name), nil))
+	}
+
+	// All entries and a file left over by an interrupted store are past their
+	// age, the entry of used.py is then loaded again
+	leftOver := filepath.Join(dir, "00", ".entry-123")
+	require.NoError(t, os.MkdirAll(filepath.Dir(leftOver), 0o755))
+	require.NoError(t, os.WriteFile(leftOver, []byte("{"), 0o644))
+
+	expired := time.Now().Add(-DefaultMaxAge - time.Hour)
+	require.NoError(t, filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		return os.Chtimes(path, expired, expired)
+	}))
+
+	_, found, err := fileCache.Load("used.py", []byte("used.py"))
+	require.NoError(t, err)
+	require.True(t, found)
+
+	removed, err := fileCache.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, leftOver)
+
+	_, found, err = fileCache.Load("used.py", []byte("used.py"))
+	require.NoError(t, err)
+	assert.True(t, found, "entries in use are kept")
+
+	_, found, err = fileCache.Load("unused.py", []byte("unused.py"))
+	require.NoError(t, err)
+	assert.False(t, found, "unused entries are removed")
+}
+
+func TestNewFileCacheWithoutDir(t *testing.T) {
+	_, err := NewFileCache(FileCacheConfig{})
+	assert.ErrorIs(t, err, ErrCreateCache)
+}
@@ -0,0 +1,48 @@
+package codeanalysis
+
+import (
+	"github.com/safedep/code/core"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// MatchResultCache persists the match results of source files across analyses so
+// that unchanged files are not parsed again. Results are keyed by the path and the
+// content of a file. Implementations must be safe for concurrent use.
+type MatchResultCache interface {
+	// Load returns the results of the file with the content and true when cached
+	Load(filePath string, content []byte) ([]common.EnrichedSignatureMatchResult, bool, error)
+
+	// Store saves the results of the file with the content, which may be empty
+	Store(filePath string, content []byte, results []common.EnrichedSignatureMatchResult) error
+}
+
+// loadCachedResults records the cached results of a file and returns true when
+// available. Cache failures are not fatal, the file is analysed instead.
+func (w *CodeAnalysisWorkflow) loadCachedResults(file core.File, content []byte) (bool, error) {
+	results, found, err := w.config.Cache.Load(file.Name(), content)
+	if err != nil {
+		log.Warnf("Failed to load cached results of %s: %v", file.Name(), err)
+		return false, nil
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	w.findingsMutex.Lock()
+	defer w.findingsMutex.Unlock()
+
+	w.findings.Stats.CachedFiles++
+
+	return true, w.recordMatchResults(results)
+}
+
+func (w *CodeAnalysisWorkflow) storeCachedResults(filePath string, content []byte,
+	results []common.EnrichedSignatureMatchResult,
+) {
+	err := w.config.Cache.Store(filePath, content, results)
+	if err != nil {
+		log.Warnf("Failed to cache results of %s: %v", filePath, err)
+	}
+}
@@ -1,9 +1,39 @@
 package codeanalysis
 
+import "github.com/safedep/xbom/pkg/common"
+
+// CodeAnalysisCallbackRegistry holds the optional callbacks of the workflow. The
+// progress callbacks are never called concurrently, even while files are analysed
+// concurrently, but they are called from the goroutines analysing the files.
 type CodeAnalysisCallbackRegistry struct {
 	OnStart  func() error
 	OnFinish func() error
 	OnErr    func(msg string, err error)
+
+	// OnFilesEnumerated is called with the number of files to analyse
+	// before any file is analysed
+	OnFilesEnumerated func(total int)
+
+	// OnFileStart is called when the analysis of a file starts. It is followed
+	// by OnFileDone, OnFileSkipped or OnFileFailed unless the analysis fails.
+	OnFileStart func(filePath string)
+
+	// OnFileDone is called when a file is analysed, after OnMatch is
+	// called for its match results
+	OnFileDone func(filePath string)
+
+	// OnFileSkipped is called when a file is not analysed because it
+	// exceeded the analysis budgets
+	OnFileSkipped func(skippedFile common.SkippedFile)
+
+	// OnFileFailed is called when the analysis of a file fails
+	// and the workflow continues in partial results mode
+	OnFileFailed func(failedFile common.FailedFile)
+
+	// OnMatch is called for every match result of an analysed file, including
+	// results reused from the cache. It is called before the suppressors and
+	// the diff classifier are applied. The analysis fails on error.
+	OnMatch func(result common.EnrichedSignatureMatchResult) error
 }
 
 func (c *CodeAnalysisCallbackRegistry) dispatchOnStart() error {
@@ -25,3 +55,40 @@ func (c *CodeAnalysisCallbackRegistry) dispatchOnErr(msg string, err error) {
 		c.OnErr(msg, err)
 	}
 }
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnFilesEnumerated(total int) {
+	if c.OnFilesEnumerated != nil {
+		c.OnFilesEnumerated(total)
+	}
+}
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnFileStart(filePath string) {
+	if c.OnFileStart != nil {
+		c.OnFileStart(filePath)
+	}
+}
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnFileDone(filePath string) {
+	if c.OnFileDone != nil {
+		c.OnFileDone(filePath)
+	}
+}
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnFileSkipped(skippedFile common.SkippedFile) {
+	if c.OnFileSkipped != nil {
+		c.OnFileSkipped(skippedFile)
+	}
+}
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnFileFailed(failedFile common.FailedFile) {
+	if c.OnFileFailed != nil {
+		c.OnFileFailed(failedFile)
+	}
+}
+
+func (c *CodeAnalysisCallbackRegistry) dispatchOnMatch(result common.EnrichedSignatureMatchResult) error {
+	if c.OnMatch != nil {
+		return c.OnMatch(result)
+	}
+	return nil
+}
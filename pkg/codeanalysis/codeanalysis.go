@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/safedep/code/core"
 	"github.com/safedep/code/fs"
 	"github.com/safedep/code/lang"
-	"github.com/safedep/code/parser"
 	"github.com/safedep/code/plugin"
 	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/dry/log"
 	"github.com/safedep/xbom/pkg/common"
 	"github.com/safedep/xbom/pkg/reporter"
 )
@@ -22,7 +28,6 @@ var (
 	ErrReportCodeAnalysisFindings = errors.New("failed to report code analysis findings")
 	ErrFinishReporting            = errors.New("failed to finish reporting")
 	ErrOnFinishCallback           = errors.New("failed to execute OnFinish callback")
-	ErrCreateLocalFileSystem      = errors.New("failed to create local filesystem")
 	ErrGetAllLanguages            = errors.New("failed to get all languages")
 	ErrCreateSourceWalker         = errors.New("failed to create source walker")
 	ErrCreateTreeWalker           = errors.New("failed to create tree walker")
@@ -34,32 +39,62 @@ var (
 	ErrMatchSignatures            = errors.New("failed to match signatures")
 	ErrRecordCodeAnalysisFindings = errors.New("failed to record code analysis findings in reporter")
 	ErrFinishReporter             = errors.New("failed to finish reporter")
+	ErrApplySuppressors           = errors.New("failed to apply suppressors")
+	ErrApplySuppressor            = errors.New("failed to apply suppressor")
+	ErrApplyDiffClassifier        = errors.New("failed to apply diff classifier")
+	ErrClassifyEvidence           = errors.New("failed to classify evidence")
+	ErrOnMatchCallback            = errors.New("failed to execute OnMatch callback")
+	ErrRecordMatchResults         = errors.New("failed to record match results in streaming reporter")
 )
 
 type CodeAnalysisWorkflow struct {
 	config    CodeAnalysisWorkflowConfig
 	findings  common.CodeAnalysisFindings
 	reporters []reporter.Reporter
+
+	// streamingReporters are the reporters receiving match results of every file
+	streamingReporters []reporter.StreamingReporter
+
+	// findingsMutex guards the findings while files are analysed concurrently
+	// and serializes the progress callbacks
+	findingsMutex sync.Mutex
 }
 
 func NewCodeAnalysisWorkflow(config CodeAnalysisWorkflowConfig, reporters []reporter.Reporter) *CodeAnalysisWorkflow {
+	streamingReporters := []reporter.StreamingReporter{}
+	for _, r := range reporters {
+		if streamingReporter, ok := r.(reporter.StreamingReporter); ok {
+			streamingReporters = append(streamingReporters, streamingReporter)
+		}
+	}
+
 	return &CodeAnalysisWorkflow{
 		config: config,
 		findings: common.CodeAnalysisFindings{
 			SignatureWiseMatchResults: make(map[string][]common.EnrichedSignatureMatchResult),
+			SuppressedMatchResults:    make(map[string][]common.EnrichedSignatureMatchResult),
 		},
-		reporters: reporters,
+		reporters:          reporters,
+		streamingReporters: streamingReporters,
 	}
 }
 
 func (w *CodeAnalysisWorkflow) Execute() (*common.CodeAnalysisFindings, error) {
+	return w.ExecuteContext(context.Background())
+}
+
+// ExecuteContext executes the workflow until completion or cancellation of the context.
+// Files exceeding the analysis budgets of the config are skipped without failing the
+// workflow and are available in the SkippedFiles of the findings. In partial results
+// mode, files whose analysis failed are available in the FailedFiles of the findings.
+func (w *CodeAnalysisWorkflow) ExecuteContext(ctx context.Context) (*common.CodeAnalysisFindings, error) {
 	err := w.config.Callbacks.dispatchOnStart()
 	if err != nil {
 		w.config.Callbacks.dispatchOnErr(ErrOnStartCallback.Error(), err)
 		return nil, fmt.Errorf("%w: %w", ErrOnStartCallback, err)
 	}
 
-	err = w.executeInternal()
+	err = w.executeInternal(ctx)
 	if err != nil {
 		w.config.Callbacks.dispatchOnErr(ErrPerformCodeAnalysis.Error(), err)
 		return nil, fmt.Errorf("%w: %w", ErrPerformCodeAnalysis, err)
@@ -86,13 +121,8 @@ func (w *CodeAnalysisWorkflow) Execute() (*common.CodeAnalysisFindings, error) {
 	return &w.findings, nil
 }
 
-func (w *CodeAnalysisWorkflow) executeInternal() error {
-	fileSystem, err := fs.NewLocalFileSystem(fs.LocalFileSystemConfig{
-		AppDirectories: []string{w.config.SourcePath},
-	})
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrCreateLocalFileSystem, err)
-	}
+func (w *CodeAnalysisWorkflow) executeInternal(ctx context.Context) error {
+	fileSystem := newSourceFileSystem(w.config.SourcePath, w.config.PathExcluder, w.config.PathFilter)
 
 	allLanguages, err := lang.AllLanguages()
 	if err != nil {
@@ -104,9 +134,41 @@ func (w *CodeAnalysisWorkflow) executeInternal() error {
 		return fmt.Errorf("%w: %w", ErrCreateSourceWalker, err)
 	}
 
-	treeWalker, err := parser.NewWalkingParser(walker, allLanguages)
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrCreateTreeWalker, err)
+	concurrency := w.config.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	treeWalker := &budgetTreeWalker{
+		sourceWalker: walker,
+		concurrency:  concurrency,
+		maxFileSize:  w.config.MaxFileSize,
+		fileTimeout:  w.config.FileTimeout,
+		onEnumerate:  w.config.Callbacks.dispatchOnFilesEnumerated,
+		onFileStart: func(file core.File) {
+			w.findingsMutex.Lock()
+			defer w.findingsMutex.Unlock()
+
+			w.config.Callbacks.dispatchOnFileStart(file.Name())
+		},
+		onFileDone: func(file core.File) {
+			w.findingsMutex.Lock()
+			defer w.findingsMutex.Unlock()
+
+			w.config.Callbacks.dispatchOnFileDone(file.Name())
+		},
+		onSkip: func(skippedFile common.SkippedFile) {
+			w.findingsMutex.Lock()
+			defer w.findingsMutex.Unlock()
+
+			w.findings.SkippedFiles = append(w.findings.SkippedFiles, skippedFile)
+			w.config.Callbacks.dispatchOnFileSkipped(skippedFile)
+		},
+		onFail: w.recordFailedFile,
+	}
+
+	if w.config.Cache != nil {
+		treeWalker.cached = w.loadCachedResults
 	}
 
 	callgraphPlugin, err := w.setupCallgraphPlugin()
@@ -122,14 +184,85 @@ func (w *CodeAnalysisWorkflow) executeInternal() error {
 		return fmt.Errorf("%w: %w", ErrCreatePluginExecutor, err)
 	}
 
-	err = pluginExecutor.Execute(context.Background(), fileSystem)
+	err = pluginExecutor.Execute(ctx, fileSystem)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrExecutePlugin, err)
 	}
 
+	w.findings.Stats.ExcludedPaths = fileSystem.excludedPaths
+
+	w.sortFindings()
+
 	return nil
 }
 
+// sortFindings orders the findings of concurrently analysed files by their
+// path in the order of the source walk, so that the findings are identical
+// to those of a sequential analysis
+func (w *CodeAnalysisWorkflow) sortFindings() {
+	for _, matchResults := range []map[string][]common.EnrichedSignatureMatchResult{
+		w.findings.SignatureWiseMatchResults,
+		w.findings.SuppressedMatchResults,
+	} {
+		for _, signatureMatchResults := range matchResults {
+			sort.SliceStable(signatureMatchResults, func(i, j int) bool {
+				return compareWalkOrder(signatureMatchResults[i].FilePath, signatureMatchResults[j].FilePath) < 0
+			})
+		}
+	}
+
+	sort.SliceStable(w.findings.SkippedFiles, func(i, j int) bool {
+		return compareWalkOrder(w.findings.SkippedFiles[i].Path, w.findings.SkippedFiles[j].Path) < 0
+	})
+
+	sort.SliceStable(w.findings.FailedFiles, func(i, j int) bool {
+		return compareWalkOrder(w.findings.FailedFiles[i].Path, w.findings.FailedFiles[j].Path) < 0
+	})
+}
+
+// recordFailedFile records the failure of a file in partial results mode
+// and returns true when the analysis can continue with other files
+func (w *CodeAnalysisWorkflow) recordFailedFile(file core.File, err error) bool {
+	if !w.config.PartialResults || !isFileFailure(err) {
+		return false
+	}
+
+	log.Warnf("Failed to analyse %s, continuing with partial results: %v", file.Name(), err)
+
+	w.findingsMutex.Lock()
+	defer w.findingsMutex.Unlock()
+
+	failedFile := common.FailedFile{Path: file.Name(), Err: err}
+
+	w.findings.FailedFiles = append(w.findings.FailedFiles, failedFile)
+	w.config.Callbacks.dispatchOnFileFailed(failedFile)
+
+	return true
+}
+
+// isFileFailure returns true unless the error is a failure of the workflow, which
+// would fail the analysis of every other file alike
+func isFileFailure(err error) bool {
+	for _, workflowErr := range []error{
+		ErrOnMatchCallback,
+		ErrApplyDiffClassifier,
+		ErrApplySuppressors,
+		ErrRecordMatchResults,
+	} {
+		if errors.Is(err, workflowErr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareWalkOrder compares paths in the lexical pre-order of filepath.WalkDir,
+// where the files of a directory are visited in the order of their names
+func compareWalkOrder(a, b string) int {
+	return slices.Compare(strings.Split(filepath.ToSlash(a), "/"), strings.Split(filepath.ToSlash(b), "/"))
+}
+
 func (w *CodeAnalysisWorkflow) setupCallgraphPlugin() (core.Plugin, error) {
 	signatureMatcher, err := callgraph.NewSignatureMatcher(w.config.SignaturesToMatch)
 	if err != nil {
@@ -147,19 +280,93 @@ func (w *CodeAnalysisWorkflow) setupCallgraphPlugin() (core.Plugin, error) {
 			return fmt.Errorf("%w: %w", ErrMatchSignatures, err)
 		}
 
+		results := make([]common.EnrichedSignatureMatchResult, 0, len(signatureMatches))
 		for _, signatureMatch := range signatureMatches {
-			w.findings.SignatureWiseMatchResults[signatureMatch.MatchedSignature.Id] = append(w.findings.SignatureWiseMatchResults[signatureMatch.MatchedSignature.Id], common.EnrichedSignatureMatchResult{
+			result := common.EnrichedSignatureMatchResult{
 				SignatureMatchResult: signatureMatch,
 				TreeData:             treeData,
-			})
+			}
+
+			result.ResolveEvidenceMetadata()
+			results = append(results, result)
 		}
 
-		return nil
+		if !commitVisit(cg.Tree) {
+			return errVisitAbandoned
+		}
+
+		if w.config.Cache != nil {
+			w.storeCachedResults(cg.FileName, *treeData, results)
+		}
+
+		w.findingsMutex.Lock()
+		defer w.findingsMutex.Unlock()
+
+		return w.recordMatchResults(results)
 	}
 
 	return callgraph.NewCallGraphPlugin(callgraphCallback), nil
 }
 
+// recordMatchResults applies the diff classifier and the suppressors on the match
+// results of a file, adds them to the findings and streams them to the streaming
+// reporters. The findings mutex must be held.
+func (w *CodeAnalysisWorkflow) recordMatchResults(results []common.EnrichedSignatureMatchResult) error {
+	for _, result := range results {
+		err := w.config.Callbacks.dispatchOnMatch(result)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrOnMatchCallback, err)
+		}
+	}
+
+	classifiedResults, err := w.classifyResults(results)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyDiffClassifier, err)
+	}
+
+	activeResults, suppressedResults, err := w.suppressResults(classifiedResults)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrApplySuppressors, err)
+	}
+
+	// Retaining the results as matched would keep the content
+	// and the parse tree of every matched file in memory
+	for i := range activeResults {
+		activeResults[i].Detach()
+	}
+
+	for i := range suppressedResults {
+		suppressedResults[i].Detach()
+	}
+
+	for _, result := range activeResults {
+		signatureId := result.MatchedSignature.GetId()
+		w.findings.SignatureWiseMatchResults[signatureId] = append(w.findings.SignatureWiseMatchResults[signatureId], result)
+	}
+
+	for _, result := range suppressedResults {
+		signatureId := result.MatchedSignature.GetId()
+		w.findings.SuppressedMatchResults[signatureId] = append(w.findings.SuppressedMatchResults[signatureId], result)
+	}
+
+	if len(activeResults)+len(suppressedResults) == 0 {
+		return nil
+	}
+
+	streamedResults := make([]common.EnrichedSignatureMatchResult, 0, len(activeResults)+len(suppressedResults))
+	streamedResults = append(streamedResults, activeResults...)
+	streamedResults = append(streamedResults, suppressedResults...)
+
+	for _, streamingReporter := range w.streamingReporters {
+		err := streamingReporter.RecordMatchResults(streamedResults)
+		if err != nil {
+			return fmt.Errorf("%w %s: %w", ErrRecordMatchResults, streamingReporter.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func (w *CodeAnalysisWorkflow) reportCodeAnalysisFindings() error {
 	for _, reporter := range w.reporters {
 		err := reporter.RecordCodeAnalysisFindings(&w.findings)
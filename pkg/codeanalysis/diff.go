@@ -0,0 +1,77 @@
+package codeanalysis
+
+import (
+	"fmt"
+
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// DiffClassifier compares evidences with a base revision of the code in
+// differential scans. Like suppressors, it classifies evidences as soon as
+// their file is analysed, files in no particular order.
+type DiffClassifier interface {
+	Classify(result *common.EnrichedSignatureMatchResult,
+		condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata) (common.DiffStatus, error)
+}
+
+// classifyResults classifies every evidence of the match results of a file and
+// splits the match results so that all evidences of a result have the same diff status
+func (w *CodeAnalysisWorkflow) classifyResults(results []common.EnrichedSignatureMatchResult) (
+	[]common.EnrichedSignatureMatchResult, error,
+) {
+	if w.config.DiffClassifier == nil {
+		return results, nil
+	}
+
+	classifiedResults := []common.EnrichedSignatureMatchResult{}
+	for i := range results {
+		resultsByStatus, err := w.classify(&results[i])
+		if err != nil {
+			return nil, err
+		}
+
+		classifiedResults = append(classifiedResults, resultsByStatus...)
+	}
+
+	return classifiedResults, nil
+}
+
+func (w *CodeAnalysisWorkflow) classify(result *common.EnrichedSignatureMatchResult) ([]common.EnrichedSignatureMatchResult, error) {
+	classifiedResults := []common.EnrichedSignatureMatchResult{}
+
+	for j := range result.MatchedConditions {
+		condition := &result.MatchedConditions[j]
+
+		for k := range condition.Evidences {
+			metadata := result.Metadata(j, k)
+
+			status, err := w.config.DiffClassifier.Classify(result, condition, metadata)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrClassifyEvidence, err)
+			}
+
+			idx := -1
+			for i := range classifiedResults {
+				if classifiedResults[i].DiffStatus == status {
+					idx = i
+					break
+				}
+			}
+
+			if idx < 0 {
+				classifiedResult := *result
+				classifiedResult.MatchedConditions = []callgraph.MatchedCondition{}
+				classifiedResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{}
+				classifiedResult.DiffStatus = status
+
+				classifiedResults = append(classifiedResults, classifiedResult)
+				idx = len(classifiedResults) - 1
+			}
+
+			appendEvidence(&classifiedResults[idx], condition, condition.Evidences[k], metadata)
+		}
+	}
+
+	return classifiedResults, nil
+}
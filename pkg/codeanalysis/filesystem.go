@@ -0,0 +1,132 @@
+package codeanalysis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/safedep/code/core"
+)
+
+// PathExcluder excludes files and directories from the analysis
+type PathExcluder interface {
+	// Excluded is called with slash separated paths relative to the source path.
+	// Excluded directories are not traversed.
+	Excluded(path string, isDir bool) bool
+}
+
+// sourceFile is a file in the source directory. Like the local files of the
+// code analysis framework, its name is the path including the source directory.
+type sourceFile struct {
+	path string
+}
+
+var _ core.File = (*sourceFile)(nil)
+
+func (f *sourceFile) Name() string {
+	return f.path
+}
+
+func (f *sourceFile) Reader() (io.ReadCloser, error) {
+	return os.Open(f.path)
+}
+
+func (f *sourceFile) IsApp() bool {
+	return true
+}
+
+func (f *sourceFile) IsImport() bool {
+	return false
+}
+
+// sourceFileSystem enumerates the application files in the source directory,
+// skipping the paths excluded by the excluder and the files rejected by the filter
+type sourceFileSystem struct {
+	sourcePath string
+	excluder   PathExcluder
+	filter     func(path string) bool
+
+	// excludedPaths is the number of files and directories excluded by the excluder
+	excludedPaths int
+}
+
+var _ core.ImportAwareFileSystem = (*sourceFileSystem)(nil)
+
+func newSourceFileSystem(sourcePath string, excluder PathExcluder, filter func(path string) bool) *sourceFileSystem {
+	return &sourceFileSystem{
+		sourcePath: sourcePath,
+		excluder:   excluder,
+		filter:     filter,
+	}
+}
+
+func (s *sourceFileSystem) Enumerate(ctx context.Context, callback func(core.File) error) error {
+	return s.EnumerateApp(ctx, callback)
+}
+
+func (s *sourceFileSystem) EnumerateApp(ctx context.Context, callback func(core.File) error) error {
+	return filepath.WalkDir(s.sourcePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error walking %s: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("enumeration cancelled by context: %w", ctx.Err())
+		default:
+		}
+
+		relPath, err := filepath.Rel(s.sourcePath, path)
+		if err != nil {
+			return fmt.Errorf("error getting relative path: %w", err)
+		}
+
+		if relPath == "." {
+			return nil
+		}
+
+		relPath = filepath.ToSlash(relPath)
+
+		if s.excluder != nil && s.excluder.Excluded(relPath, entry.IsDir()) {
+			s.excludedPaths++
+
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		if s.filter != nil && !s.filter(relPath) {
+			return nil
+		}
+
+		return callback(&sourceFile{path: path})
+	})
+}
+
+func (s *sourceFileSystem) EnumerateImports(_ context.Context, _ func(core.File) error) error {
+	return nil
+}
+
+func (s *sourceFileSystem) Find(ctx context.Context, name string) (core.File, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("find cancelled by context: %w", ctx.Err())
+	default:
+	}
+
+	fullPath := filepath.Join(s.sourcePath, name)
+	if st, err := os.Stat(fullPath); err == nil && !st.IsDir() {
+		return &sourceFile{path: fullPath}, nil
+	}
+
+	return nil, fmt.Errorf("file not found: %s", name)
+}
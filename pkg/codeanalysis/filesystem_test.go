@@ -0,0 +1,57 @@
+package codeanalysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/safedep/code/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPathExcluder struct {
+	visited []string
+}
+
+func (e *testPathExcluder) Excluded(path string, isDir bool) bool {
+	e.visited = append(e.visited, path)
+	return isDir && strings.HasSuffix(path, "node_modules")
+}
+
+func TestSourceFileSystemEnumerateApp(t *testing.T) {
+	sourcePath := t.TempDir()
+	for _, file := range []string{"main.py", "app/util.py", "node_modules/lib/index.js", "app/node_modules/x.js"} {
+		fullPath := filepath.Join(sourcePath, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(""), 0o644))
+	}
+
+	excluder := &testPathExcluder{}
+	fileSystem := newSourceFileSystem(sourcePath, excluder, func(path string) bool {
+		return path != "main.py"
+	})
+
+	files := []string{}
+	err := fileSystem.EnumerateApp(context.Background(), func(file core.File) error {
+		assert.True(t, file.IsApp())
+		files = append(files, file.Name())
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(sourcePath, "app", "util.py")}, files)
+	assert.Equal(t, 2, fileSystem.excludedPaths)
+
+	// Excluded directories are not traversed
+	assert.NotContains(t, excluder.visited, "node_modules/lib")
+
+	file, err := fileSystem.Find(context.Background(), "app/util.py")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sourcePath, "app", "util.py"), file.Name())
+
+	_, err = fileSystem.Find(context.Background(), "missing.py")
+	assert.Error(t, err)
+}
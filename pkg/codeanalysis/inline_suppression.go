@@ -0,0 +1,226 @@
+package codeanalysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/lang"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// InlineSuppressionSource is the source of suppressions created from comments in code
+const InlineSuppressionSource = "inline"
+
+// inlineSuppressionRegexp matches the suppression directive within a comment eg.
+// `xbom:ignore golang.crypto.hash reason="checksums only"`. Multiple comma separated
+// signature IDs or glob patterns are supported. Without any ID, all signatures are ignored.
+var (
+	inlineSuppressionRegexp       = regexp.MustCompile(`xbom:ignore(?:[ \t]+(.*))?$`)
+	inlineSuppressionReasonRegexp = regexp.MustCompile(`reason="([^"]*)"`)
+)
+
+type inlineSuppressionDirective struct {
+	patterns []string
+	reason   string
+}
+
+func (d *inlineSuppressionDirective) matches(signatureId string) bool {
+	if len(d.patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range d.patterns {
+		if matched, _ := path.Match(pattern, signatureId); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inlineSuppressionFile holds the directives of a file by 0-based line number
+type inlineSuppressionFile struct {
+	directives   map[int]*inlineSuppressionDirective
+	commentLines map[int]bool
+}
+
+// InlineSuppressor suppresses evidences annotated with a `xbom:ignore` comment
+// on the same line as the call or on the comment lines immediately above it.
+//
+//	// xbom:ignore golang.crypto.hash reason="checksums only"
+//	sum := sha256.Sum256(data)
+//
+//	h = hashlib.md5(data)  # xbom:ignore python.crypto.hash
+type InlineSuppressor struct {
+	files map[string]*inlineSuppressionFile
+}
+
+var _ Suppressor = (*InlineSuppressor)(nil)
+
+func NewInlineSuppressor() *InlineSuppressor {
+	return &InlineSuppressor{
+		files: make(map[string]*inlineSuppressionFile),
+	}
+}
+
+func (s *InlineSuppressor) Name() string {
+	return "inline"
+}
+
+func (s *InlineSuppressor) Suppress(result *common.EnrichedSignatureMatchResult,
+	_ *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata,
+) (*common.Suppression, error) {
+	if metadata.CallerIdentifierMetadata == nil {
+		return nil, nil
+	}
+
+	file, err := s.file(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file.directives) == 0 {
+		return nil, nil
+	}
+
+	signatureId := result.MatchedSignature.GetId()
+	line := int(metadata.CallerIdentifierMetadata.StartLine)
+
+	// Directive on the same line as the call
+	if directive, ok := file.directives[line]; ok && directive.matches(signatureId) {
+		return inlineSuppression(directive), nil
+	}
+
+	// Directives in the comment block immediately above the call
+	for above := line - 1; above >= 0 && file.commentLines[above]; above-- {
+		if directive, ok := file.directives[above]; ok && directive.matches(signatureId) {
+			return inlineSuppression(directive), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// file returns the parsed directives of the file of a match result, reading the
+// file from disk only when the tree data is not available
+func (s *InlineSuppressor) file(result *common.EnrichedSignatureMatchResult) (*inlineSuppressionFile, error) {
+	if file, ok := s.files[result.FilePath]; ok {
+		return file, nil
+	}
+
+	var content []byte
+	if result.TreeData != nil {
+		content = *result.TreeData
+	} else {
+		data, err := os.ReadFile(result.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", result.FilePath, err)
+		}
+
+		content = data
+	}
+
+	file, err := parseInlineSuppressions(content, result.MatchedLanguageCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inline suppressions of %s: %w", result.FilePath, err)
+	}
+
+	s.files[result.FilePath] = file
+
+	return file, nil
+}
+
+// parseInlineSuppressions finds the directives in the comments of the content, using
+// the comment nodes of the parse tree so that directives within string literals or
+// after a comment marker within a string are ignored
+func parseInlineSuppressions(content []byte, languageCode core.LanguageCode) (*inlineSuppressionFile, error) {
+	file := &inlineSuppressionFile{
+		directives:   make(map[int]*inlineSuppressionDirective),
+		commentLines: make(map[int]bool),
+	}
+
+	language, err := lang.GetLanguage(string(languageCode))
+	if err != nil {
+		return nil, err
+	}
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	parser.SetLanguage(language.Language())
+
+	tree, err := parser.ParseCtx(context.Background(), nil, content)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tree.Close()
+
+	walkCommentNodes(tree.RootNode(), func(node *sitter.Node) {
+		startLine := int(node.StartPoint().Row)
+
+		// Comments with only whitespace before them on their first line form
+		// the comment block above a call
+		lineStart := int(node.StartByte()) - int(node.StartPoint().Column)
+		ownLine := strings.TrimSpace(string(content[lineStart:node.StartByte()])) == ""
+
+		for i, line := range strings.Split(node.Content(content), "\n") {
+			if ownLine {
+				file.commentLines[startLine+i] = true
+			}
+
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\r"), "*/")
+			if directive := parseInlineSuppressionDirective(line); directive != nil {
+				file.directives[startLine+i] = directive
+			}
+		}
+	})
+
+	return file, nil
+}
+
+// walkCommentNodes calls the function with every comment node of the tree, such as
+// the comment node of Go, Python and JavaScript and the line and block comment
+// nodes of Java
+func walkCommentNodes(node *sitter.Node, fn func(node *sitter.Node)) {
+	if strings.HasSuffix(node.Type(), "comment") {
+		fn(node)
+		return
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		walkCommentNodes(node.Child(i), fn)
+	}
+}
+
+func parseInlineSuppressionDirective(line string) *inlineSuppressionDirective {
+	match := inlineSuppressionRegexp.FindStringSubmatch(strings.TrimRight(line, " \t"))
+	if match == nil {
+		return nil
+	}
+
+	directive := &inlineSuppressionDirective{}
+	if reason := inlineSuppressionReasonRegexp.FindStringSubmatch(match[1]); reason != nil {
+		directive.reason = reason[1]
+	}
+
+	if fields := strings.Fields(match[1]); len(fields) > 0 && !strings.HasPrefix(fields[0], "reason=") {
+		directive.patterns = strings.Split(fields[0], ",")
+	}
+
+	return directive
+}
+
+func inlineSuppression(directive *inlineSuppressionDirective) *common.Suppression {
+	return &common.Suppression{
+		Source: InlineSuppressionSource,
+		Reason: directive.reason,
+	}
+}
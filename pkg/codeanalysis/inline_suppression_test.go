@@ -0,0 +1,137 @@
+package codeanalysis
+
+import (
+	"testing"
+
+	"github.com/safedep/code/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInlineSuppressions(t *testing.T) {
+	cases := []struct {
+		name         string
+		content      string
+		language     core.LanguageCode
+		line         int
+		wantPatterns []string
+		wantReason   string
+		wantComment  bool
+	}{
+		{
+			name:        "all signatures",
+			content:     "// xbom:ignore\ncall()",
+			language:    core.LanguageCodeGo,
+			line:        0,
+			wantComment: true,
+		},
+		{
+			name:         "signature with reason",
+			content:      "// xbom:ignore golang.crypto.hash reason=\"checksum only\"",
+			language:     core.LanguageCodeGo,
+			line:         0,
+			wantPatterns: []string{"golang.crypto.hash"},
+			wantReason:   "checksum only",
+			wantComment:  true,
+		},
+		{
+			name:       "reason without signature",
+			content:    "call() // xbom:ignore reason=\"test\"",
+			language:   core.LanguageCodeGo,
+			line:       0,
+			wantReason: "test",
+		},
+		{
+			name:         "multiple signatures in trailing comment",
+			content:      "x = 1\nh = hashlib.md5(data)  # xbom:ignore python.crypto.*,python.network.*",
+			language:     core.LanguageCodePython,
+			line:         1,
+			wantPatterns: []string{"python.crypto.*", "python.network.*"},
+		},
+		{
+			name:         "carriage return line endings",
+			content:      "// xbom:ignore golang.crypto.hash\r\ncall()\r\n",
+			language:     core.LanguageCodeGo,
+			line:         0,
+			wantPatterns: []string{"golang.crypto.hash"},
+			wantComment:  true,
+		},
+		{
+			name:        "carriage return after reason",
+			content:     "h = hashlib.md5(data)  # xbom:ignore reason=\"checksum\"\r\nx = 1\r\n",
+			language:    core.LanguageCodePython,
+			line:        0,
+			wantReason:  "checksum",
+			wantComment: false,
+		},
+		{
+			name:         "block comment",
+			content:      "/* xbom:ignore java.crypto.* */\nclass A {}",
+			language:     core.LanguageCodeJava,
+			line:         0,
+			wantPatterns: []string{"java.crypto.*"},
+			wantComment:  true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			file, err := parseInlineSuppressions([]byte(test.content), test.language)
+			require.NoError(t, err)
+
+			require.Contains(t, file.directives, test.line)
+			assert.Equal(t, test.wantPatterns, file.directives[test.line].patterns)
+			assert.Equal(t, test.wantReason, file.directives[test.line].reason)
+			assert.Equal(t, test.wantComment, file.commentLines[test.line])
+		})
+	}
+}
+
+func TestParseInlineSuppressionsIgnoresDirectiveOutsideComment(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		language core.LanguageCode
+	}{
+		{
+			name:     "python string",
+			content:  `s = "xbom:ignore"`,
+			language: core.LanguageCodePython,
+		},
+		{
+			name:     "python string with comment marker",
+			content:  `s = "# xbom:ignore"`,
+			language: core.LanguageCodePython,
+		},
+		{
+			name:     "go string with comment marker",
+			content:  "package main\n\nvar s = \"// xbom:ignore\"\n",
+			language: core.LanguageCodeGo,
+		},
+		{
+			name:     "javascript url with comment marker",
+			content:  `fetch("https://example.com/#xbom:ignore")`,
+			language: core.LanguageCodeJavascript,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			file, err := parseInlineSuppressions([]byte(test.content), test.language)
+			require.NoError(t, err)
+
+			assert.Empty(t, file.directives)
+			assert.Empty(t, file.commentLines)
+		})
+	}
+}
+
+func TestInlineSuppressionDirectiveMatches(t *testing.T) {
+	directive := &inlineSuppressionDirective{patterns: []string{"golang.crypto.*", "golang.network.tcp"}}
+
+	assert.True(t, directive.matches("golang.crypto.hash"))
+	assert.True(t, directive.matches("golang.network.tcp"))
+	assert.False(t, directive.matches("golang.network.udp"))
+
+	assert.True(t, (&inlineSuppressionDirective{}).matches("any.signature"))
+}
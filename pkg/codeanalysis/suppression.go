@@ -0,0 +1,142 @@
+package codeanalysis
+
+import (
+	"fmt"
+
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// Suppressor decides whether an evidence of a signature match must be suppressed.
+// Suppressed evidences are moved to CodeAnalysisFindings.SuppressedMatchResults
+// before the findings are reported. Evidences are suppressed as soon as their file
+// is analysed, files in no particular order, so suppressions must not depend on
+// the order of files.
+type Suppressor interface {
+	Name() string
+
+	// Suppress returns a non-nil suppression when the evidence with the metadata must be suppressed
+	Suppress(result *common.EnrichedSignatureMatchResult,
+		condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata) (*common.Suppression, error)
+}
+
+// suppressResults runs the suppressors on every evidence of the match results of a
+// file and splits them into active and suppressed match results. The first suppressor
+// to suppress an evidence wins.
+func (w *CodeAnalysisWorkflow) suppressResults(results []common.EnrichedSignatureMatchResult) (
+	[]common.EnrichedSignatureMatchResult, []common.EnrichedSignatureMatchResult, error,
+) {
+	if len(w.config.Suppressors) == 0 {
+		return results, nil, nil
+	}
+
+	activeResults := []common.EnrichedSignatureMatchResult{}
+	suppressedResults := []common.EnrichedSignatureMatchResult{}
+
+	for i := range results {
+		result := &results[i]
+
+		activeResult := *result
+		activeResult.MatchedConditions = []callgraph.MatchedCondition{}
+		activeResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{}
+
+		resultSuppressions := []common.EnrichedSignatureMatchResult{}
+
+		for j := range result.MatchedConditions {
+			condition := &result.MatchedConditions[j]
+
+			for k := range condition.Evidences {
+				metadata := result.Metadata(j, k)
+
+				suppression, err := w.suppress(result, condition, metadata)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				if suppression == nil {
+					appendEvidence(&activeResult, condition, condition.Evidences[k], metadata)
+					continue
+				}
+
+				resultSuppressions = addSuppressedEvidence(resultSuppressions, result,
+					suppression, condition, condition.Evidences[k], metadata)
+			}
+		}
+
+		if len(activeResult.MatchedConditions) > 0 {
+			activeResults = append(activeResults, activeResult)
+		}
+
+		suppressedResults = append(suppressedResults, resultSuppressions...)
+	}
+
+	return activeResults, suppressedResults, nil
+}
+
+func (w *CodeAnalysisWorkflow) suppress(result *common.EnrichedSignatureMatchResult,
+	condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata,
+) (*common.Suppression, error) {
+	for _, suppressor := range w.config.Suppressors {
+		suppression, err := suppressor.Suppress(result, condition, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrApplySuppressor, suppressor.Name(), err)
+		}
+
+		if suppression != nil {
+			return suppression, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// addSuppressedEvidence adds the evidence to the suppressed result having the same
+// suppression, creating a new result for the suppression when required
+func addSuppressedEvidence(suppressedResults []common.EnrichedSignatureMatchResult,
+	result *common.EnrichedSignatureMatchResult, suppression *common.Suppression,
+	condition *callgraph.MatchedCondition, evidence callgraph.MatchedEvidence,
+	metadata callgraph.EvidenceMetadata,
+) []common.EnrichedSignatureMatchResult {
+	idx := -1
+	for i := range suppressedResults {
+		if *suppressedResults[i].Suppression == *suppression {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		suppressedResult := *result
+		suppressedResult.MatchedConditions = []callgraph.MatchedCondition{}
+		suppressedResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{}
+		suppressedResult.Suppression = suppression
+
+		suppressedResults = append(suppressedResults, suppressedResult)
+		idx = len(suppressedResults) - 1
+	}
+
+	appendEvidence(&suppressedResults[idx], condition, evidence, metadata)
+
+	return suppressedResults
+}
+
+// appendEvidence adds an evidence and its metadata to a result, grouped with the
+// preceding evidence of the result when both belong to the same condition
+func appendEvidence(result *common.EnrichedSignatureMatchResult,
+	condition *callgraph.MatchedCondition, evidence callgraph.MatchedEvidence,
+	metadata callgraph.EvidenceMetadata,
+) {
+	lastCondition := len(result.MatchedConditions) - 1
+	if lastCondition >= 0 && result.MatchedConditions[lastCondition].Condition == condition.Condition {
+		result.MatchedConditions[lastCondition].Evidences = append(
+			result.MatchedConditions[lastCondition].Evidences, evidence)
+		result.EvidenceMetadata[lastCondition] = append(result.EvidenceMetadata[lastCondition], metadata)
+		return
+	}
+
+	result.MatchedConditions = append(result.MatchedConditions, callgraph.MatchedCondition{
+		Condition: condition.Condition,
+		Evidences: []callgraph.MatchedEvidence{evidence},
+	})
+	result.EvidenceMetadata = append(result.EvidenceMetadata, []callgraph.EvidenceMetadata{metadata})
+}
@@ -0,0 +1,349 @@
+package codeanalysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/lang"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+	sitter "github.com/smacker/go-tree-sitter"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrReadSourceFile  = errors.New("failed to read source file")
+	ErrParseSourceFile = errors.New("failed to parse source file")
+
+	// errVisitAbandoned is returned by visits abandoned for exceeding the time
+	// budget of the file, it is never reported as the walker has moved on
+	errVisitAbandoned = errors.New("visit abandoned")
+)
+
+// budgetTreeWalker parses the source files enumerated by a source walker like the
+// walking parser of the code analysis framework. Unlike it, parsing honors the
+// context, files larger than maxFileSize are not parsed and parsing and visiting of
+// a file is abandoned after fileTimeout. Such files are reported to onSkip. Files for which
+// cached returns true are not parsed, the walk stops when it fails.
+//
+// All files are enumerated before parsing so that their number is reported to
+// onEnumerate up front. Every file is then reported to onFileStart followed by
+// either onFileDone, onSkip or onFail, unless the walk fails. Failures to read,
+// parse or visit a file are reported to onFail, which returns true to continue
+// with other files. All hooks are optional.
+//
+// Files are parsed and visited by concurrency workers, so the visitor and the hooks
+// other than onEnumerate must be safe for concurrent use. The walk stops on the
+// first error.
+type budgetTreeWalker struct {
+	sourceWalker core.SourceWalker
+	concurrency  int
+	maxFileSize  int64
+	fileTimeout  time.Duration
+	onEnumerate  func(total int)
+	onFileStart  func(file core.File)
+	onFileDone   func(file core.File)
+	onSkip       func(common.SkippedFile)
+	onFail       func(file core.File, err error) bool
+	cached       func(file core.File, content []byte) (bool, error)
+}
+
+var _ core.TreeWalker = (*budgetTreeWalker)(nil)
+
+func (w *budgetTreeWalker) Walk(ctx context.Context, fs core.ImportAwareFileSystem, visitor core.TreeVisitor) error {
+	enumerator := &enumeratingSourceVisitor{}
+	err := w.sourceWalker.Walk(ctx, fs, enumerator)
+	if err != nil {
+		return err
+	}
+
+	if w.onEnumerate != nil {
+		w.onEnumerate(len(enumerator.files))
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	files := make(chan core.File)
+	sourceVisitor := &budgetSourceVisitor{
+		ctx:     groupCtx,
+		walker:  w,
+		visitor: visitor,
+	}
+
+	for i := 0; i < max(w.concurrency, 1); i++ {
+		group.Go(func() error {
+			for file := range files {
+				if err := sourceVisitor.VisitFile(file); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+dispatch:
+	for _, file := range enumerator.files {
+		select {
+		case files <- file:
+		case <-groupCtx.Done():
+			break dispatch
+		}
+	}
+
+	close(files)
+
+	// A failed worker cancels the walk, so its error is the cause
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("analysis cancelled by context: %w", err)
+	}
+
+	return nil
+}
+
+// enumeratingSourceVisitor collects the enumerated files
+type enumeratingSourceVisitor struct {
+	files []core.File
+}
+
+func (v *enumeratingSourceVisitor) VisitFile(f core.File) error {
+	v.files = append(v.files, f)
+	return nil
+}
+
+type budgetSourceVisitor struct {
+	ctx     context.Context
+	walker  *budgetTreeWalker
+	visitor core.TreeVisitor
+}
+
+func (v *budgetSourceVisitor) VisitFile(f core.File) error {
+	if err := v.ctx.Err(); err != nil {
+		return fmt.Errorf("analysis cancelled by context: %w", err)
+	}
+
+	if v.walker.onFileStart != nil {
+		v.walker.onFileStart(f)
+	}
+
+	data, exceeded, err := v.readFile(f)
+	if err != nil {
+		return v.fail(f, fmt.Errorf("%w %s: %w", ErrReadSourceFile, f.Name(), err))
+	}
+
+	if exceeded {
+		v.skip(f, common.SkipReasonFileSize)
+		return nil
+	}
+
+	if v.walker.cached != nil {
+		cached, err := v.walker.cached(f, data)
+		if err != nil {
+			return err
+		}
+
+		if cached {
+			v.done(f)
+			return nil
+		}
+	}
+
+	language, exists := lang.ResolveLanguageFromPath(f.Name())
+	if !exists {
+		return v.fail(f, fmt.Errorf("%w %s: failed to resolve language from file path", ErrParseSourceFile, f.Name()))
+	}
+
+	// A parser is created for every file because a cancelled parse may leave
+	// the cancellation flag of the parser set for the next parse
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	parser.SetLanguage(language.Language())
+
+	fileCtx := v.ctx
+	if v.walker.fileTimeout > 0 {
+		var cancel context.CancelFunc
+		fileCtx, cancel = context.WithTimeout(v.ctx, v.walker.fileTimeout)
+		defer cancel()
+	}
+
+	tree, err := parser.ParseCtx(fileCtx, nil, data)
+	if err != nil {
+		if v.ctx.Err() != nil {
+			return fmt.Errorf("analysis cancelled by context: %w", v.ctx.Err())
+		}
+
+		if fileCtx.Err() != nil {
+			v.skip(f, common.SkipReasonTimeout)
+			return nil
+		}
+
+		return v.fail(f, fmt.Errorf("%w %s: %w", ErrParseSourceFile, f.Name(), err))
+	}
+
+	parsedTree := &parseTree{
+		tree: tree,
+		data: &data,
+		file: f,
+		lang: language,
+	}
+
+	// Building the callgraph and matching signatures cannot be interrupted, so the
+	// visit is abandoned when the file exceeds its time budget or the analysis is
+	// cancelled. Results of an abandoned visit are discarded by commitVisit.
+	visited := make(chan error, 1)
+	go func() {
+		visited <- v.visitor.VisitTree(parsedTree)
+	}()
+
+	select {
+	case err = <-visited:
+	case <-fileCtx.Done():
+		if !parsedTree.abandon() {
+			// The results of the visit are already being recorded
+			err = <-visited
+			break
+		}
+
+		if v.ctx.Err() != nil {
+			return fmt.Errorf("analysis cancelled by context: %w", v.ctx.Err())
+		}
+
+		v.skip(f, common.SkipReasonTimeout)
+		return nil
+	}
+
+	if err != nil {
+		return v.fail(f, err)
+	}
+
+	v.done(f)
+	return nil
+}
+
+// readFile reads the file unless it is larger than the size budget,
+// reading no more than the budget in that case
+func (v *budgetSourceVisitor) readFile(f core.File) ([]byte, bool, error) {
+	r, err := f.Reader()
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Errorf("Failed to close file %s: %v", f.Name(), err)
+		}
+	}()
+
+	if v.walker.maxFileSize <= 0 {
+		data, err := io.ReadAll(r)
+		return data, false, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, v.walker.maxFileSize+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(data)) > v.walker.maxFileSize {
+		return nil, true, nil
+	}
+
+	return data, false, nil
+}
+
+func (v *budgetSourceVisitor) done(f core.File) {
+	if v.walker.onFileDone != nil {
+		v.walker.onFileDone(f)
+	}
+}
+
+func (v *budgetSourceVisitor) skip(f core.File, reason common.SkipReason) {
+	log.Warnf("Skipping analysis of %s: %s budget exceeded", f.Name(), reason)
+
+	if v.walker.onSkip != nil {
+		v.walker.onSkip(common.SkippedFile{Path: f.Name(), Reason: reason})
+	}
+}
+
+// fail returns the error unless onFail tolerates it
+func (v *budgetSourceVisitor) fail(f core.File, err error) error {
+	if v.walker.onFail != nil && v.walker.onFail(f, err) {
+		return nil
+	}
+
+	return err
+}
+
+type parseTree struct {
+	tree *sitter.Tree
+	data *[]byte
+	file core.File
+	lang core.Language
+
+	// mutex guards the state of the visit, whose results are either
+	// committed or discarded when the visit is abandoned
+	mutex     sync.Mutex
+	committed bool
+	abandoned bool
+}
+
+// commitVisit is called by visitors before recording the results of visiting a
+// tree. It returns false when the visit was abandoned for exceeding the time
+// budget of the file, in which case the results must be discarded.
+func commitVisit(tree core.ParseTree) bool {
+	t, ok := tree.(*parseTree)
+	if !ok {
+		return true
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.abandoned {
+		return false
+	}
+
+	t.committed = true
+	return true
+}
+
+// abandon abandons the visit of the tree unless its results are already committed
+func (t *parseTree) abandon() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.committed {
+		return false
+	}
+
+	t.abandoned = true
+	return true
+}
+
+var _ core.ParseTree = (*parseTree)(nil)
+
+func (t *parseTree) Tree() *sitter.Tree {
+	return t.tree
+}
+
+func (t *parseTree) Data() (*[]byte, error) {
+	return t.data, nil
+}
+
+func (t *parseTree) File() (core.File, error) {
+	return t.file, nil
+}
+
+func (t *parseTree) Language() (core.Language, error) {
+	return t.lang, nil
+}
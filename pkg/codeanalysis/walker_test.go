@@ -0,0 +1,235 @@
+package codeanalysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/fs"
+	"github.com/safedep/code/lang"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTreeVisitor struct {
+	visited []string
+}
+
+func (v *testTreeVisitor) VisitTree(tree core.ParseTree) error {
+	file, err := tree.File()
+	if err != nil {
+		return err
+	}
+
+	v.visited = append(v.visited, filepath.Base(file.Name()))
+	return nil
+}
+
+func TestBudgetTreeWalker(t *testing.T) {
+	sourcePath := t.TempDir()
+	files := map[string]string{
+		"small.py": "import os\nos.getcwd()\n",
+		"large.py": strings.Repeat("# padding\n", 100),
+		"slow.py":  strings.Repeat("x = foo(1, 2, [3, 4], {'a': b})\n", 20000),
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(sourcePath, name), []byte(content), 0o644))
+	}
+
+	allLanguages, err := lang.AllLanguages()
+	require.NoError(t, err)
+
+	sourceWalker, err := fs.NewSourceWalker(fs.SourceWalkerConfig{}, allLanguages)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		maxFileSize int64
+		fileTimeout time.Duration
+		visited     []string
+		skipped     []common.SkippedFile
+	}{
+		{
+			name:    "no budgets",
+			visited: []string{"large.py", "slow.py", "small.py"},
+		},
+		{
+			name:        "size budget",
+			maxFileSize: 500,
+			visited:     []string{"small.py"},
+			skipped: []common.SkippedFile{
+				{Path: filepath.Join(sourcePath, "large.py"), Reason: common.SkipReasonFileSize},
+				{Path: filepath.Join(sourcePath, "slow.py"), Reason: common.SkipReasonFileSize},
+			},
+		},
+		{
+			name:        "time budget",
+			maxFileSize: 10 * 1024 * 1024,
+			fileTimeout: 100 * time.Millisecond,
+			visited:     []string{"large.py", "small.py"},
+			skipped: []common.SkippedFile{
+				{Path: filepath.Join(sourcePath, "slow.py"), Reason: common.SkipReasonTimeout},
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var skipped []common.SkippedFile
+			var total, done int
+			walker := &budgetTreeWalker{
+				sourceWalker: sourceWalker,
+				maxFileSize:  test.maxFileSize,
+				fileTimeout:  test.fileTimeout,
+				onEnumerate: func(count int) {
+					total = count
+				},
+				onFileDone: func(core.File) {
+					done++
+				},
+				onSkip: func(skippedFile common.SkippedFile) {
+					skipped = append(skipped, skippedFile)
+				},
+			}
+
+			visitor := &testTreeVisitor{}
+			err := walker.Walk(context.Background(), newSourceFileSystem(sourcePath, nil, nil), visitor)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.visited, visitor.visited)
+			assert.Equal(t, test.skipped, skipped)
+			assert.Equal(t, len(files), total)
+			assert.Equal(t, len(test.visited), done)
+		})
+	}
+}
+
+// slowTreeVisitor is a visitor taking delay to visit slow.py, which commits
+// the results of the visit like the callgraph callback
+type slowTreeVisitor struct {
+	delay     time.Duration
+	committed chan bool
+}
+
+func (v *slowTreeVisitor) VisitTree(tree core.ParseTree) error {
+	file, err := tree.File()
+	if err != nil {
+		return err
+	}
+
+	if filepath.Base(file.Name()) != "slow.py" {
+		commitVisit(tree)
+		return nil
+	}
+
+	time.Sleep(v.delay)
+	v.committed <- commitVisit(tree)
+
+	return nil
+}
+
+func TestBudgetTreeWalkerSlowVisit(t *testing.T) {
+	sourcePath := t.TempDir()
+	for _, name := range []string{"main.py", "slow.py"} {
+		require.NoError(t, os.WriteFile(filepath.Join(sourcePath, name), []byte("print(1)\n"), 0o644))
+	}
+
+	allLanguages, err := lang.AllLanguages()
+	require.NoError(t, err)
+
+	sourceWalker, err := fs.NewSourceWalker(fs.SourceWalkerConfig{}, allLanguages)
+	require.NoError(t, err)
+
+	var skipped []common.SkippedFile
+	var done []string
+	walker := &budgetTreeWalker{
+		sourceWalker: sourceWalker,
+		fileTimeout:  100 * time.Millisecond,
+		onFileDone: func(file core.File) {
+			done = append(done, filepath.Base(file.Name()))
+		},
+		onSkip: func(skippedFile common.SkippedFile) {
+			skipped = append(skipped, skippedFile)
+		},
+	}
+
+	visitor := &slowTreeVisitor{delay: time.Second, committed: make(chan bool, 1)}
+
+	start := time.Now()
+	err = walker.Walk(context.Background(), newSourceFileSystem(sourcePath, nil, nil), visitor)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), visitor.delay, "the slow visit is abandoned")
+	assert.Equal(t, []string{"main.py"}, done)
+	assert.Equal(t, []common.SkippedFile{
+		{Path: filepath.Join(sourcePath, "slow.py"), Reason: common.SkipReasonTimeout},
+	}, skipped)
+
+	assert.False(t, <-visitor.committed, "results of the abandoned visit are discarded")
+}
+
+func TestBudgetTreeWalkerCancelled(t *testing.T) {
+	sourcePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "main.py"), []byte("print(1)\n"), 0o644))
+
+	allLanguages, err := lang.AllLanguages()
+	require.NoError(t, err)
+
+	sourceWalker, err := fs.NewSourceWalker(fs.SourceWalkerConfig{}, allLanguages)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	visitor := &testTreeVisitor{}
+	walker := &budgetTreeWalker{sourceWalker: sourceWalker}
+
+	err = walker.Walk(ctx, newSourceFileSystem(sourcePath, nil, nil), visitor)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, visitor.visited)
+}
+
+func TestBudgetTreeWalkerFailures(t *testing.T) {
+	sourcePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "main.py"), []byte("print(1)\n"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(sourcePath, "missing.py"), filepath.Join(sourcePath, "broken.py")))
+
+	allLanguages, err := lang.AllLanguages()
+	require.NoError(t, err)
+
+	sourceWalker, err := fs.NewSourceWalker(fs.SourceWalkerConfig{}, allLanguages)
+	require.NoError(t, err)
+
+	for _, tolerate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("tolerate %t", tolerate), func(t *testing.T) {
+			var failed []string
+			walker := &budgetTreeWalker{
+				sourceWalker: sourceWalker,
+				onFail: func(file core.File, err error) bool {
+					assert.ErrorIs(t, err, ErrReadSourceFile)
+
+					failed = append(failed, filepath.Base(file.Name()))
+					return tolerate
+				},
+			}
+
+			visitor := &testTreeVisitor{}
+			err := walker.Walk(context.Background(), newSourceFileSystem(sourcePath, nil, nil), visitor)
+			assert.Equal(t, []string{"broken.py"}, failed)
+
+			if tolerate {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"main.py"}, visitor.visited)
+			} else {
+				assert.ErrorIs(t, err, ErrReadSourceFile)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package codeanalysis
 
 import (
+	"time"
+
 	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
 	"github.com/safedep/xbom/pkg/common"
 )
@@ -10,4 +12,42 @@ type CodeAnalysisWorkflowConfig struct {
 	SourcePath        string
 	SignaturesToMatch []*callgraphv1.Signature
 	Callbacks         CodeAnalysisCallbackRegistry
+
+	// Suppressors are applied in order on every evidence before reporting
+	Suppressors []Suppressor
+
+	// PathExcluder excludes files and directories from the analysis. Nothing
+	// is excluded when nil.
+	PathExcluder PathExcluder
+
+	// PathFilter limits the analysis to the files for which it returns true. It is
+	// called with slash separated paths relative to SourcePath. All files are
+	// analysed when nil.
+	PathFilter func(path string) bool
+
+	// Concurrency is the number of files analysed concurrently. It defaults
+	// to the number of CPUs when zero.
+	Concurrency int
+
+	// MaxFileSize is the size budget in bytes of a source file. Larger files are
+	// skipped. There is no limit when zero.
+	MaxFileSize int64
+
+	// FileTimeout is the time budget for parsing a source file and matching its
+	// signatures. Files not analysed within the budget are skipped. There is no
+	// limit when zero.
+	FileTimeout time.Duration
+
+	// Cache reuses the match results of files analysed before with the same
+	// content. Results are not cached when nil.
+	Cache MatchResultCache
+
+	// PartialResults records files whose analysis failed in the FailedFiles of the
+	// findings and continues with other files. The analysis fails on the first such
+	// file otherwise. Failures of callbacks, suppressors, the diff classifier and
+	// reporters always fail the analysis.
+	PartialResults bool
+
+	// DiffClassifier assigns a diff status to every evidence before reporting
+	DiffClassifier DiffClassifier
 }
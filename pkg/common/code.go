@@ -2,11 +2,182 @@ package common
 
 import "github.com/safedep/code/plugin/callgraph"
 
+// Suppression describes why evidences of a signature match were suppressed
+type Suppression struct {
+	// Source of the suppression eg. "baseline"
+	Source string
+
+	// Optional human readable reason for the suppression
+	Reason string
+}
+
+// DiffStatus describes an evidence in comparison with a base revision of the code
+type DiffStatus string
+
+const (
+	// DiffStatusIntroduced is an evidence not found in the base revision
+	DiffStatusIntroduced DiffStatus = "introduced"
+
+	// DiffStatusPreExisting is an evidence also found in the base revision
+	DiffStatusPreExisting DiffStatus = "pre-existing"
+)
+
 type EnrichedSignatureMatchResult struct {
 	callgraph.SignatureMatchResult
+
+	// TreeData is the content of the file. It is available only until the match
+	// results of the file are suppressed, results in the findings are detached.
 	TreeData *[]byte
+
+	// EvidenceMetadata is the metadata of the evidences in MatchedConditions, with the
+	// same indexes, resolved when the evidences are matched. Evidences of results
+	// restored from the analysis cache have no tree nodes, so their metadata is
+	// available only here.
+	EvidenceMetadata [][]callgraph.EvidenceMetadata
+
+	// Suppression is available only for results in CodeAnalysisFindings.SuppressedMatchResults
+	Suppression *Suppression
+
+	// DiffStatus is available only for differential scans against a base revision
+	DiffStatus DiffStatus
+}
+
+// SkipReason describes why a file was not analyzed
+type SkipReason string
+
+const (
+	// SkipReasonFileSize is used for files larger than the size budget
+	SkipReasonFileSize SkipReason = "file-size"
+
+	// SkipReasonTimeout is used for files which could not be parsed and matched within the time budget
+	SkipReasonTimeout SkipReason = "timeout"
+)
+
+// SkippedFile is a source file which was not analyzed
+type SkippedFile struct {
+	// Path of the file including the source directory, like FilePath of match results
+	Path string
+
+	Reason SkipReason
+}
+
+// FailedFile is a source file whose analysis failed, recorded only when the
+// analysis is tolerant to failures of individual files
+type FailedFile struct {
+	// Path of the file including the source directory, like FilePath of match results
+	Path string
+
+	// Err wraps the sentinel error of the failed step of the analysis
+	Err error
+}
+
+// Metadata returns the metadata of an evidence given the index of its condition in
+// MatchedConditions and its index in the evidences of the condition. The metadata is
+// resolved from the evidence when not available in EvidenceMetadata.
+func (r *EnrichedSignatureMatchResult) Metadata(condition, evidence int) callgraph.EvidenceMetadata {
+	if condition < len(r.EvidenceMetadata) && evidence < len(r.EvidenceMetadata[condition]) {
+		return r.EvidenceMetadata[condition][evidence]
+	}
+
+	matchedEvidence := r.MatchedConditions[condition].Evidences[evidence]
+	if r.TreeData != nil || matchedEvidence.CallerIdentifier == nil {
+		return matchedEvidence.Metadata(r.TreeData)
+	}
+
+	// Content of the caller identifier is not available without the tree data
+	callerIdentifier := matchedEvidence.CallerIdentifier
+	matchedEvidence.CallerIdentifier = nil
+
+	metadata := matchedEvidence.Metadata(nil)
+	metadata.CallerIdentifierMetadata = &callgraph.TreeNodeMetadata{
+		StartLine:   callerIdentifier.StartPoint().Row,
+		EndLine:     callerIdentifier.EndPoint().Row,
+		StartColumn: callerIdentifier.StartPoint().Column,
+		EndColumn:   callerIdentifier.EndPoint().Column,
+	}
+
+	return metadata
+}
+
+// ResolveEvidenceMetadata resolves the metadata of all evidences into EvidenceMetadata
+func (r *EnrichedSignatureMatchResult) ResolveEvidenceMetadata() {
+	r.EvidenceMetadata = nil
+
+	evidenceMetadata := make([][]callgraph.EvidenceMetadata, len(r.MatchedConditions))
+	for i, condition := range r.MatchedConditions {
+		evidenceMetadata[i] = make([]callgraph.EvidenceMetadata, len(condition.Evidences))
+		for j := range condition.Evidences {
+			evidenceMetadata[i][j] = r.Metadata(i, j)
+		}
+	}
+
+	r.EvidenceMetadata = evidenceMetadata
+}
+
+// Detach resolves the metadata of all evidences and replaces the evidences with
+// copies having only the namespaces of the caller and the callee, dropping the tree
+// data. Unlike the evidences created by the signature matcher, these do not keep
+// the content and the parse tree of the file in memory.
+func (r *EnrichedSignatureMatchResult) Detach() {
+	evidenceMetadata := make([][]callgraph.EvidenceMetadata, len(r.MatchedConditions))
+	matchedConditions := make([]callgraph.MatchedCondition, len(r.MatchedConditions))
+
+	for i, condition := range r.MatchedConditions {
+		evidenceMetadata[i] = make([]callgraph.EvidenceMetadata, len(condition.Evidences))
+		evidences := make([]callgraph.MatchedEvidence, len(condition.Evidences))
+
+		for j := range condition.Evidences {
+			metadata := r.Metadata(i, j)
+
+			evidenceMetadata[i][j] = metadata
+			evidences[j] = callgraph.MatchedEvidence{
+				Caller: &callgraph.CallGraphNode{Namespace: metadata.CallerNamespace},
+				Callee: &callgraph.CallGraphNode{Namespace: metadata.CalleeNamespace},
+			}
+		}
+
+		matchedConditions[i] = callgraph.MatchedCondition{
+			Condition: condition.Condition,
+			Evidences: evidences,
+		}
+	}
+
+	r.MatchedConditions = matchedConditions
+	r.EvidenceMetadata = evidenceMetadata
+	r.TreeData = nil
+}
+
+// CodeAnalysisStats are statistics of the analysed source code
+type CodeAnalysisStats struct {
+	// ExcludedPaths is the number of files and directories excluded from analysis
+	ExcludedPaths int
+
+	// CachedFiles is the number of files with match results reused from the cache
+	CachedFiles int
 }
 
 type CodeAnalysisFindings struct {
 	SignatureWiseMatchResults map[string][]EnrichedSignatureMatchResult
+
+	// SuppressedMatchResults holds the suppressed evidences, grouped by signature like
+	// SignatureWiseMatchResults. These are not part of SignatureWiseMatchResults. Reporters
+	// may use them to show suppressed findings for audit purposes.
+	SuppressedMatchResults map[string][]EnrichedSignatureMatchResult
+
+	// Stats of the analysis
+	Stats CodeAnalysisStats
+
+	// SkippedFiles are the source files not analyzed because they exceeded the
+	// analysis budgets. Findings in these files, if any, are not reported.
+	SkippedFiles []SkippedFile
+
+	// FailedFiles are the source files whose analysis failed. Findings in these
+	// files, if any, are not reported.
+	FailedFiles []FailedFile
+}
+
+// Partial returns true when the analysis of some files failed, so
+// that the findings are incomplete
+func (f *CodeAnalysisFindings) Partial() bool {
+	return len(f.FailedFiles) > 0
 }
@@ -0,0 +1,21 @@
+package common
+
+// ModelMetadata describes the machine learning model used through the SDK matched
+// by a signature. It is declared by the optional model field of signature files.
+type ModelMetadata struct {
+	// Name of the model or model family when known, such as Claude
+	Name string `yaml:"name"`
+
+	// Provider of the model, when the vendor of the signature is not the provider
+	// or the signature is not tagged as a service
+	Provider string `yaml:"provider"`
+
+	// Task of the model, such as text-generation. It is derived from the
+	// signature tags when empty.
+	Task string `yaml:"task"`
+}
+
+// IsEmpty checks if no model metadata is declared
+func (m ModelMetadata) IsEmpty() bool {
+	return m.Name == "" && m.Provider == "" && m.Task == ""
+}
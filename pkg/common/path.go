@@ -0,0 +1,22 @@
+package common
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// RelativeSourcePath returns the slash separated path of a file relative to the
+// scanned source directory. It returns false when the file is not within the
+// source directory or the relative path cannot be computed.
+func RelativeSourcePath(sourcePath, filePath string) (string, bool) {
+	if sourcePath == "" {
+		return "", false
+	}
+
+	relPath, err := filepath.Rel(sourcePath, filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(relPath), true
+}
@@ -0,0 +1,27 @@
+package common
+
+// ServiceMetadata describes the external service used through the SDK matched by a
+// signature. It is declared by optional fields of signature files, which are not
+// part of the signature schema.
+type ServiceMetadata struct {
+	// Endpoints are the URLs of the service
+	Endpoints []string `yaml:"endpoints"`
+
+	// TrustBoundary is false for services within the trust boundary of
+	// the application, such as self-hosted ones. It defaults to true.
+	TrustBoundary *bool `yaml:"trust_boundary"`
+
+	DataFlows []ServiceDataFlow `yaml:"data_flows"`
+}
+
+// ServiceDataFlow is the direction and classification of data exchanged
+// with a service. Flow is one of inbound, outbound, bi-directional or unknown.
+type ServiceDataFlow struct {
+	Flow           string `yaml:"flow"`
+	Classification string `yaml:"classification"`
+}
+
+// IsEmpty checks if no service metadata is declared
+func (m ServiceMetadata) IsEmpty() bool {
+	return len(m.Endpoints) == 0 && m.TrustBoundary == nil && len(m.DataFlows) == 0
+}
@@ -0,0 +1,286 @@
+// Package config implements the project config file, which declares the options of
+// the generate command for a repository so that they need not be repeated as flags
+// in every pipeline.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project config file
+const FileName = ".xbom.yaml"
+
+var (
+	ErrLoadConfig     = errors.New("failed to load config")
+	ErrInvalidConfig  = errors.New("invalid config")
+	ErrDiscoverConfig = errors.New("failed to discover config")
+)
+
+// Config is the project config loaded from a config file. Options not set in
+// the file are left to their defaults. Relative paths are resolved against the
+// directory of the config file when loaded.
+//
+// Example:
+//
+//	version: 0.1
+//	app_name: payments
+//	signatures:
+//	  dirs: [.xbom/signatures]
+//	  exclude_tags: [capability]
+//	paths:
+//	  exclude: [test/fixtures/]
+//	reports:
+//	  cyclonedx:
+//	    path: build/xbom.cdx.json
+//	  markdown:
+//	    path: build/xbom.md
+//	    snippet:
+//	      before_lines: 5
+//	    sections:
+//	      language_breakdown: false
+//	  html:
+//	    path: build/xbom.html
+//	    snippet:
+//	      before_lines: 0
+//	      after_lines: 0
+type Config struct {
+	Version    string           `yaml:"version"`
+	AppName    string           `yaml:"app_name"`
+	Signatures SignaturesConfig `yaml:"signatures"`
+	Paths      PathsConfig      `yaml:"paths"`
+
+	// Policy and Baseline are paths of the policy and baseline files
+	Policy   string `yaml:"policy"`
+	Baseline string `yaml:"baseline"`
+
+	Reports ReportsConfig `yaml:"reports"`
+}
+
+// SignaturesConfig declares additional signatures and the signature filters
+type SignaturesConfig struct {
+	Dirs        []string `yaml:"dirs"`
+	IncludeTags []string `yaml:"include_tags"`
+	ExcludeTags []string `yaml:"exclude_tags"`
+	Vendors     []string `yaml:"vendors"`
+	Languages   []string `yaml:"languages"`
+	IDs         []string `yaml:"ids"`
+}
+
+// PathsConfig declares gitignore style patterns of the files to analyse
+type PathsConfig struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+// ReportsConfig declares the enabled reports. A report is enabled when its path
+// is set, except for the summary which is always shown.
+type ReportsConfig struct {
+	Summary   SummaryReportConfig  `yaml:"summary"`
+	CycloneDX ReportConfig         `yaml:"cyclonedx"`
+	HTML      HTMLReportConfig     `yaml:"html"`
+	Markdown  MarkdownReportConfig `yaml:"markdown"`
+	SARIF     ReportConfig         `yaml:"sarif"`
+	JSON      ReportConfig         `yaml:"json"`
+	JSONL     ReportConfig         `yaml:"jsonl"`
+
+	// NoSnippets omits code snippets from all reports
+	NoSnippets bool `yaml:"no_snippets"`
+}
+
+type ReportConfig struct {
+	Path string `yaml:"path"`
+}
+
+// SummaryReportConfig holds the options of the summary shown on the console.
+// Options are pointers so that unset options keep their defaults.
+type SummaryReportConfig struct {
+	Limit   *int    `yaml:"limit"`
+	Stats   *bool   `yaml:"stats"`
+	Color   *bool   `yaml:"color"`
+	GroupBy *string `yaml:"group_by"`
+}
+
+type HTMLReportConfig struct {
+	Path    string        `yaml:"path"`
+	Snippet SnippetConfig `yaml:"snippet"`
+}
+
+type MarkdownReportConfig struct {
+	Path     string                 `yaml:"path"`
+	Snippet  SnippetConfig          `yaml:"snippet"`
+	Sections MarkdownSectionsConfig `yaml:"sections"`
+}
+
+// SnippetConfig holds the context lines and size limits of code snippets.
+// Options are pointers so that unset options keep their defaults, zero size
+// limits mean no limit.
+type SnippetConfig struct {
+	BeforeLines  *int `yaml:"before_lines"`
+	AfterLines   *int `yaml:"after_lines"`
+	MaxBytes     *int `yaml:"max_bytes"`
+	MaxLineChars *int `yaml:"max_line_chars"`
+}
+
+// MarkdownSectionsConfig toggles the sections of the Markdown report.
+// Sections not set are shown.
+type MarkdownSectionsConfig struct {
+	ExecutiveSummary  *bool `yaml:"executive_summary"`
+	Statistics        *bool `yaml:"statistics"`
+	TopSignatures     *bool `yaml:"top_signatures"`
+	LanguageBreakdown *bool `yaml:"language_breakdown"`
+	DetailedFindings  *bool `yaml:"detailed_findings"`
+}
+
+// Discover finds the config file in the directory or the closest parent directory
+// having one, up to the root of the git repository of the directory. Outside of a
+// git repository, only the directory itself is searched so that a config file of an
+// unrelated parent directory is never used. It returns an empty path when there is
+// no config file.
+func Discover(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDiscoverConfig, err)
+	}
+
+	rootDir, err := gitRoot(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDiscoverConfig, err)
+	}
+
+	if rootDir == "" {
+		rootDir = dir
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %w", ErrDiscoverConfig, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if dir == rootDir || parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// gitRoot returns the closest directory having a .git entry, which is a file
+// for worktrees and submodules. It returns an empty path outside of a repository.
+func gitRoot(dir string) (string, error) {
+	for {
+		_, err := os.Stat(filepath.Join(dir, ".git"))
+		if err == nil {
+			return dir, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// LoadConfig reads and validates a config file. Unknown options are
+// rejected so that typos do not go unnoticed.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadConfig, err)
+	}
+
+	var config Config
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLoadConfig, path, err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	config.resolvePaths(filepath.Dir(path))
+
+	return &config, nil
+}
+
+// Validate checks the values of the options
+func (c *Config) Validate() error {
+	if limit := c.Reports.Summary.Limit; limit != nil && *limit < 0 {
+		return fmt.Errorf("%w: reports.summary.limit must not be negative", ErrInvalidConfig)
+	}
+
+	err := c.Reports.Markdown.Snippet.validate("reports.markdown.snippet")
+	if err != nil {
+		return err
+	}
+
+	return c.Reports.HTML.Snippet.validate("reports.html.snippet")
+}
+
+func (s SnippetConfig) validate(prefix string) error {
+	for _, option := range []struct {
+		name  string
+		value *int
+	}{
+		{"before_lines", s.BeforeLines},
+		{"after_lines", s.AfterLines},
+		{"max_bytes", s.MaxBytes},
+		{"max_line_chars", s.MaxLineChars},
+	} {
+		if option.value != nil && *option.value < 0 {
+			return fmt.Errorf("%w: %s.%s must not be negative", ErrInvalidConfig, prefix, option.name)
+		}
+	}
+
+	return nil
+}
+
+// resolvePaths makes the relative paths of files and directories relative
+// to the directory of the config file. Path patterns are left as is, they
+// are always relative to the analysed directory.
+func (c *Config) resolvePaths(dir string) {
+	resolve := func(path *string) {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+
+	for i := range c.Signatures.Dirs {
+		resolve(&c.Signatures.Dirs[i])
+	}
+
+	resolve(&c.Policy)
+	resolve(&c.Baseline)
+	resolve(&c.Reports.CycloneDX.Path)
+	resolve(&c.Reports.HTML.Path)
+	resolve(&c.Reports.Markdown.Path)
+	resolve(&c.Reports.SARIF.Path)
+	resolve(&c.Reports.JSON.Path)
+	resolve(&c.Reports.JSONL.Path)
+}
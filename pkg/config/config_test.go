@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, FileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr error
+	}{
+		{
+			name: "valid config",
+			content: `version: 0.1
+app_name: payments
+paths:
+  exclude: [test/fixtures/]
+reports:
+  summary:
+    limit: 0
+  markdown:
+    path: xbom.md
+`,
+		},
+		{
+			name:    "empty config",
+			content: "",
+		},
+		{
+			name: "unknown option",
+			content: `reports:
+  markdown:
+    output: xbom.md
+`,
+			expectErr: ErrLoadConfig,
+		},
+		{
+			name: "negative summary limit",
+			content: `reports:
+  summary:
+    limit: -1
+`,
+			expectErr: ErrInvalidConfig,
+		},
+		{
+			name: "negative snippet size",
+			content: `reports:
+  markdown:
+    snippet:
+      max_bytes: -1
+`,
+			expectErr: ErrInvalidConfig,
+		},
+		{
+			name: "negative html snippet context",
+			content: `reports:
+  html:
+    snippet:
+      after_lines: -1
+`,
+			expectErr: ErrInvalidConfig,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfigFile(t, t.TempDir(), test.content)
+
+			_, err := LoadConfig(path)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, dir, `signatures:
+  dirs: [signatures, /opt/signatures]
+paths:
+  include: [src/**]
+policy: .xbom-policy.yaml
+reports:
+  summary:
+    stats: false
+    group_by: ""
+  json:
+    path: build/xbom.json
+  markdown:
+    path: build/xbom.md
+    snippet:
+      before_lines: 0
+    sections:
+      statistics: false
+  no_snippets: true
+`)
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "signatures"), "/opt/signatures"}, config.Signatures.Dirs)
+	assert.Equal(t, []string{"src/**"}, config.Paths.Include)
+	assert.Equal(t, filepath.Join(dir, ".xbom-policy.yaml"), config.Policy)
+	assert.Empty(t, config.Baseline)
+	assert.Equal(t, filepath.Join(dir, "build", "xbom.json"), config.Reports.JSON.Path)
+	assert.Equal(t, filepath.Join(dir, "build", "xbom.md"), config.Reports.Markdown.Path)
+	assert.Empty(t, config.Reports.SARIF.Path)
+
+	require.NotNil(t, config.Reports.Summary.Stats)
+	assert.False(t, *config.Reports.Summary.Stats)
+	assert.Nil(t, config.Reports.Summary.Limit)
+	require.NotNil(t, config.Reports.Summary.GroupBy)
+	assert.Empty(t, *config.Reports.Summary.GroupBy)
+
+	require.NotNil(t, config.Reports.Markdown.Snippet.BeforeLines)
+	assert.Zero(t, *config.Reports.Markdown.Snippet.BeforeLines)
+	assert.Nil(t, config.Reports.Markdown.Snippet.AfterLines)
+	assert.True(t, config.Reports.NoSnippets)
+
+	require.NotNil(t, config.Reports.Markdown.Sections.Statistics)
+	assert.False(t, *config.Reports.Markdown.Sections.Statistics)
+	assert.Nil(t, config.Reports.Markdown.Sections.DetailedFindings)
+}
+
+func TestDiscover(t *testing.T) {
+	rootDir := t.TempDir()
+	projectDir := filepath.Join(rootDir, "project")
+	nestedDir := filepath.Join(projectDir, "services", "api")
+	require.NoError(t, os.MkdirAll(nestedDir, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(projectDir, ".git"), 0o755))
+
+	// Config files above the git root are not used
+	writeConfigFile(t, rootDir, "app_name: root\n")
+
+	path, err := Discover(nestedDir)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	configPath := writeConfigFile(t, projectDir, "app_name: project\n")
+
+	for _, dir := range []string{projectDir, nestedDir} {
+		path, err := Discover(dir)
+		require.NoError(t, err)
+		assert.Equal(t, configPath, path, dir)
+	}
+
+	// The closest config file is used
+	nestedConfigPath := writeConfigFile(t, nestedDir, "app_name: api\n")
+
+	path, err = Discover(nestedDir)
+	require.NoError(t, err)
+	assert.Equal(t, nestedConfigPath, path)
+}
+
+func TestDiscoverOutsideGitRepository(t *testing.T) {
+	rootDir := t.TempDir()
+	scanDir := filepath.Join(rootDir, "scan")
+	require.NoError(t, os.Mkdir(scanDir, 0o755))
+
+	writeConfigFile(t, rootDir, "app_name: root\n")
+
+	path, err := Discover(scanDir)
+	require.NoError(t, err)
+	assert.Empty(t, path, "only the scanned directory is searched")
+
+	configPath := writeConfigFile(t, scanDir, "app_name: scan\n")
+
+	path, err = Discover(scanDir)
+	require.NoError(t, err)
+	assert.Equal(t, configPath, path)
+}
@@ -0,0 +1,41 @@
+package gitdiff
+
+import (
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/baseline"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// Classifier classifies evidences as introduced or pre-existing by comparing
+// their fingerprints with the findings of a scan of the base revision. Like a
+// baseline, each base evidence matches at most one evidence so that additional
+// occurrences of an identical call are classified as introduced.
+type Classifier struct {
+	base *baseline.Suppressor
+}
+
+// NewClassifier creates a classifier from the findings of a scan of the base
+// revision extracted in baseSourcePath. The source path must be the scanned
+// directory of the evidences to classify.
+func NewClassifier(baseFindings *common.CodeAnalysisFindings, baseSourcePath, sourcePath string) *Classifier {
+	baseBaseline := baseline.NewBaseline(common.ToolMetadata{}, baseSourcePath, baseFindings)
+
+	return &Classifier{
+		base: baseline.NewSuppressor(baseBaseline, sourcePath),
+	}
+}
+
+func (c *Classifier) Classify(result *common.EnrichedSignatureMatchResult,
+	condition *callgraph.MatchedCondition, metadata callgraph.EvidenceMetadata,
+) (common.DiffStatus, error) {
+	found, err := c.base.Suppress(result, condition, metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if found != nil {
+		return common.DiffStatusPreExisting, nil
+	}
+
+	return common.DiffStatusIntroduced, nil
+}
@@ -0,0 +1,436 @@
+package gitdiff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/safedep/dry/log"
+)
+
+var (
+	ErrOpenRepository   = errors.New("failed to open git repository")
+	ErrResolveRevision  = errors.New("failed to resolve git revision")
+	ErrFindMergeBase    = errors.New("failed to find merge base")
+	ErrListChangedFiles = errors.New("failed to list changed files")
+	ErrExtractBaseFiles = errors.New("failed to extract base revision files")
+)
+
+// Changes are the files changed in the working tree of a git repository since
+// the merge base of a revision and HEAD, similar to `git diff <revision>...`
+// including uncommitted and untracked files.
+type Changes struct {
+	// BaseCommit is the hash of the merge base commit
+	BaseCommit string
+
+	// Files are the changed files, as slash separated paths relative
+	// to the source path. Deleted files are not included.
+	Files []string
+
+	// sourcePrefix is the slash separated path of the source
+	// directory within the repository, empty for the repository root
+	sourcePrefix string
+	baseTree     *object.Tree
+	files        map[string]bool
+
+	// basePaths are the repository paths in the base revision of the
+	// files renamed since, keyed by their path relative to the source path
+	basePaths map[string]string
+}
+
+// ChangedFiles finds the files changed under the source path since a revision
+// such as a branch, tag or commit. The git repository is discovered from the
+// source path and read locally, without any network access.
+func ChangedFiles(sourcePath, revision string) (*Changes, error) {
+	repository, err := git.PlainOpenWithOptions(sourcePath, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrOpenRepository, sourcePath, err)
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOpenRepository, err)
+	}
+
+	sourcePrefix, err := repositoryPath(worktree.Filesystem.Root(), sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOpenRepository, err)
+	}
+
+	baseCommit, err := mergeBase(repository, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	baseTree, err := baseCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListChangedFiles, err)
+	}
+
+	headTree, err := headTree(repository)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListChangedFiles, err)
+	}
+
+	changedPaths, err := committedChanges(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListChangedFiles, err)
+	}
+
+	worktreePaths, err := worktreeChanges(repository, worktree, headTree, sourcePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListChangedFiles, err)
+	}
+
+	for _, repoPath := range worktreePaths {
+		if _, ok := changedPaths[repoPath]; !ok {
+			changedPaths[repoPath] = repoPath
+		}
+	}
+
+	changes := &Changes{
+		BaseCommit:   baseCommit.Hash.String(),
+		Files:        []string{},
+		sourcePrefix: sourcePrefix,
+		baseTree:     baseTree,
+		files:        map[string]bool{},
+		basePaths:    map[string]string{},
+	}
+
+	for repoPath, basePath := range changedPaths {
+		if path, ok := changes.sourceRelativePath(repoPath); ok {
+			changes.Files = append(changes.Files, path)
+			changes.files[path] = true
+
+			if basePath != repoPath {
+				changes.basePaths[path] = basePath
+			}
+		}
+	}
+
+	sort.Strings(changes.Files)
+
+	return changes, nil
+}
+
+// Contains checks if a slash separated path relative to the source path is changed
+func (c *Changes) Contains(path string) bool {
+	return c.files[path]
+}
+
+// ExtractBase writes the base revision of the changed files into a directory,
+// keeping their paths relative to the source path. Renamed files are written
+// at their new path. Files which did not exist in the base revision are skipped.
+func (c *Changes) ExtractBase(dir string) error {
+	for _, path := range c.Files {
+		repoPath := path
+		if c.sourcePrefix != "" {
+			repoPath = c.sourcePrefix + "/" + path
+		}
+
+		if basePath, ok := c.basePaths[path]; ok {
+			repoPath = basePath
+		}
+
+		file, err := c.baseTree.File(repoPath)
+		if errors.Is(err, object.ErrFileNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrExtractBaseFiles, repoPath, err)
+		}
+
+		if err := writeBlob(file, filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrExtractBaseFiles, repoPath, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Changes) sourceRelativePath(repoPath string) (string, bool) {
+	if c.sourcePrefix == "" {
+		return repoPath, true
+	}
+
+	path, found := strings.CutPrefix(repoPath, c.sourcePrefix+"/")
+	return path, found
+}
+
+func mergeBase(repository *git.Repository, revision string) (*object.Commit, error) {
+	revisionHash, err := repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrResolveRevision, revision, err)
+	}
+
+	revisionCommit, err := repository.CommitObject(*revisionHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrResolveRevision, revision, err)
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return nil, fmt.Errorf("%w: HEAD: %w", ErrResolveRevision, err)
+	}
+
+	headCommit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("%w: HEAD: %w", ErrResolveRevision, err)
+	}
+
+	bases, err := revisionCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFindMergeBase, err)
+	}
+
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("%w: %s and HEAD have no common ancestor", ErrFindMergeBase, revision)
+	}
+
+	log.Debugf("Using merge base %s of %s and HEAD", bases[0].Hash, revision)
+
+	return bases[0], nil
+}
+
+func headTree(repository *git.Repository) (*object.Tree, error) {
+	head, err := repository.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	headCommit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return headCommit.Tree()
+}
+
+// committedChanges returns the repository paths of the files added, modified or
+// renamed between the base tree and HEAD, mapped to their path in the base tree
+func committedChanges(baseTree, headTree *object.Tree) (map[string]string, error) {
+	treeChanges, err := object.DiffTreeWithOptions(context.Background(), baseTree, headTree,
+		object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	for _, change := range treeChanges {
+		if change.To.Name == "" {
+			continue
+		}
+
+		paths[change.To.Name] = change.To.Name
+		if change.From.Name != "" {
+			paths[change.To.Name] = change.From.Name
+		}
+	}
+
+	return paths, nil
+}
+
+// worktreeChanges returns the repository paths of the files under the source prefix
+// which are staged, modified or untracked and not ignored, compared to HEAD. Unlike
+// the status of the whole working tree, only files under the source prefix are read,
+// and like git, tracked files are hashed only when their size or modification time
+// differs from the index.
+func worktreeChanges(repository *git.Repository, worktree *git.Worktree, headTree *object.Tree,
+	sourcePrefix string) ([]string, error) {
+	index, err := repository.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	root := worktree.Filesystem.Root()
+	underSource := func(repoPath string) bool {
+		return sourcePrefix == "" || strings.HasPrefix(repoPath, sourcePrefix+"/")
+	}
+
+	changed := []string{}
+	tracked := map[string]bool{}
+	for _, entry := range index.Entries {
+		if !underSource(entry.Name) || entry.Mode == filemode.Submodule {
+			continue
+		}
+
+		tracked[entry.Name] = true
+
+		info, err := os.Lstat(filepath.Join(root, filepath.FromSlash(entry.Name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		headEntry, err := headTree.FindEntry(entry.Name)
+		if err != nil && !errors.Is(err, object.ErrEntryNotFound) && !errors.Is(err, object.ErrDirectoryNotFound) {
+			return nil, err
+		}
+
+		staged := headEntry == nil || headEntry.Hash != entry.Hash
+		if staged {
+			changed = append(changed, entry.Name)
+			continue
+		}
+
+		if info.Size() == int64(entry.Size) && info.ModTime().Equal(entry.ModifiedAt) {
+			continue
+		}
+
+		hash, err := worktreeHash(filepath.Join(root, filepath.FromSlash(entry.Name)), info)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash != entry.Hash {
+			changed = append(changed, entry.Name)
+		}
+	}
+
+	patterns, err := gitignore.ReadPatterns(worktree.Filesystem, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := gitignore.NewMatcher(append(patterns, worktree.Excludes...))
+
+	sourceDir := filepath.Join(root, filepath.FromSlash(sourcePrefix))
+	err = filepath.WalkDir(sourceDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		repoPath := filepath.ToSlash(relPath)
+		if repoPath == "." {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == git.GitDirName || matcher.Match(strings.Split(repoPath, "/"), true) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !tracked[repoPath] && !matcher.Match(strings.Split(repoPath, "/"), false) {
+			changed = append(changed, repoPath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// worktreeHash returns the blob hash of a file of the working tree, the
+// target of a symbolic link being its content
+func worktreeHash(filePath string, info fs.FileInfo) (plumbing.Hash, error) {
+	var content []byte
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(filePath)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		content = []byte(filepath.ToSlash(target))
+	} else {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		content = data
+	}
+
+	return plumbing.ComputeHash(plumbing.BlobObject, content), nil
+}
+
+// repositoryPath returns the slash separated path of the
+// source directory relative to the repository root
+func repositoryPath(root, sourcePath string) (string, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+
+	absSourcePath, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedSourcePath, err := filepath.EvalSymlinks(absSourcePath)
+	if err != nil {
+		return "", err
+	}
+
+	relPath, err := filepath.Rel(resolvedRoot, resolvedSourcePath)
+	if err != nil {
+		return "", err
+	}
+
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("source path %s is outside the repository %s", sourcePath, root)
+	}
+
+	if relPath == "." {
+		return "", nil
+	}
+
+	return filepath.ToSlash(relPath), nil
+}
+
+func writeBlob(file *object.File, path string) error {
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Errorf("Failed to close blob reader %s: %v", file.Name, err)
+		}
+	}()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	fd, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fd, reader)
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
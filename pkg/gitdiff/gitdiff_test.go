@@ -0,0 +1,159 @@
+package gitdiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepository struct {
+	t        *testing.T
+	root     string
+	worktree *git.Worktree
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	root := t.TempDir()
+
+	repository, err := git.PlainInit(root, false)
+	require.NoError(t, err)
+
+	worktree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	return &testRepository{t: t, root: root, worktree: worktree}
+}
+
+func (r *testRepository) write(path, content string) {
+	fullPath := filepath.Join(r.root, filepath.FromSlash(path))
+	require.NoError(r.t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+	require.NoError(r.t, os.WriteFile(fullPath, []byte(content), 0o644))
+}
+
+func (r *testRepository) commit(message string) plumbing.Hash {
+	require.NoError(r.t, r.worktree.AddWithOptions(&git.AddOptions{All: true}))
+
+	hash, err := r.worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(r.t, err)
+
+	return hash
+}
+
+func (r *testRepository) checkout(branch string, create bool) {
+	require.NoError(r.t, r.worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	}))
+}
+
+func TestChangedFiles(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.write("app/main.py", "import openai\n")
+	repo.write("app/util.py", "import os\n")
+	repo.write("docs/README.md", "docs\n")
+	repo.write(".gitignore", "*.log\n")
+	baseCommit := repo.commit("base")
+
+	repo.checkout("main", true)
+	repo.checkout("feature", true)
+
+	// Committed, uncommitted and untracked changes
+	repo.write("app/main.py", "import openai\nimport anthropic\n")
+	repo.commit("feature")
+	repo.write("app/util.py", "import os\nimport subprocess\n")
+	repo.write("app/new/agent.py", "import anthropic\n")
+	repo.write("app/debug.log", "ignored\n")
+	repo.write("docs/README.md", "updated docs\n")
+
+	changes, err := ChangedFiles(filepath.Join(repo.root, "app"), "main")
+	require.NoError(t, err)
+
+	assert.Equal(t, baseCommit.String(), changes.BaseCommit)
+	assert.Equal(t, []string{"main.py", "new/agent.py", "util.py"}, changes.Files)
+	assert.True(t, changes.Contains("main.py"))
+	assert.False(t, changes.Contains("README.md"))
+
+	baseDir := t.TempDir()
+	require.NoError(t, changes.ExtractBase(baseDir))
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "main.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "import openai\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(baseDir, "util.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "import os\n", string(content))
+
+	// New files do not exist in the base revision
+	assert.NoFileExists(t, filepath.Join(baseDir, "new", "agent.py"))
+}
+
+func TestChangedFilesRenamed(t *testing.T) {
+	repo := newTestRepository(t)
+	agent := "import anthropic\n\nclient = anthropic.Anthropic()\nclient.messages.create()\n"
+	repo.write("app/agent.py", agent)
+	repo.write("app/main.py", "import agent\n")
+	repo.commit("base")
+
+	repo.checkout("main", true)
+	repo.checkout("feature", true)
+
+	require.NoError(t, os.Remove(filepath.Join(repo.root, "app", "agent.py")))
+	repo.write("app/agents/claude.py", agent+"import openai\n")
+	repo.commit("rename")
+
+	changes, err := ChangedFiles(filepath.Join(repo.root, "app"), "main")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"agents/claude.py"}, changes.Files)
+
+	// The base revision of a renamed file is written at its new path
+	baseDir := t.TempDir()
+	require.NoError(t, changes.ExtractBase(baseDir))
+
+	content, err := os.ReadFile(filepath.Join(baseDir, "agents", "claude.py"))
+	require.NoError(t, err)
+	assert.Equal(t, agent, string(content))
+}
+
+func TestChangedFilesUsesMergeBase(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.write("a.py", "a\n")
+	baseCommit := repo.commit("base")
+
+	repo.checkout("main", true)
+	repo.write("main.py", "main\n")
+	repo.commit("main")
+
+	// Changes on the revision after the merge base are not included
+	repo.checkout("master", false)
+	repo.write("b.py", "b\n")
+	repo.commit("feature")
+
+	changes, err := ChangedFiles(repo.root, "main")
+	require.NoError(t, err)
+
+	assert.Equal(t, baseCommit.String(), changes.BaseCommit)
+	assert.Equal(t, []string{"b.py"}, changes.Files)
+}
+
+func TestChangedFilesErrors(t *testing.T) {
+	_, err := ChangedFiles(t.TempDir(), "main")
+	assert.ErrorIs(t, err, ErrOpenRepository)
+
+	repo := newTestRepository(t)
+	repo.write("a.py", "a\n")
+	repo.commit("base")
+
+	_, err = ChangedFiles(repo.root, "does-not-exist")
+	assert.ErrorIs(t, err, ErrResolveRevision)
+}
@@ -0,0 +1,212 @@
+package pathfilter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/safedep/dry/log"
+)
+
+var ErrInvalidPattern = errors.New("invalid path pattern")
+
+// IgnoreFiles are the files with gitignore style patterns honored in every directory
+var IgnoreFiles = []string{".gitignore", ".xbomignore"}
+
+// DefaultExcludes are directories of dependencies, version control and tooling which
+// are not part of the application source code. Build output directories are excluded
+// only at the root of the source path, as code is commonly kept in such directories
+// elsewhere, eg. a build package.
+var DefaultExcludes = []string{
+	".git/",
+	".hg/",
+	".svn/",
+	"node_modules/",
+	"bower_components/",
+	"vendor/",
+	".venv/",
+	"venv/",
+	"__pycache__/",
+	".tox/",
+	"site-packages/",
+	"/dist/",
+	"/build/",
+	"/target/",
+}
+
+type FilterConfig struct {
+	// SourcePath is the scanned directory, ignore files are read from it and its subdirectories
+	SourcePath string
+
+	// Include limits the analysis to files matching any of the patterns
+	Include []string
+
+	// Exclude skips files and directories matching any of the patterns. These
+	// take precedence over the ignore files and the default excludes.
+	Exclude []string
+
+	// DefaultExcludes skips the directories in DefaultExcludes
+	DefaultExcludes bool
+
+	// IgnoreFiles honors the patterns in the IgnoreFiles of the scanned directories
+	IgnoreFiles bool
+}
+
+// Filter decides which paths are excluded from analysis using gitignore style
+// patterns. Patterns without a slash match a name at any depth, patterns with
+// a slash are relative to the source path and `**` matches any number of
+// directories.
+type Filter struct {
+	config          FilterConfig
+	include         []gitignore.Pattern
+	exclude         []gitignore.Pattern
+	defaultExcludes []gitignore.Pattern
+
+	// ignorePatterns are read from the ignore files of directories in loadedDirs,
+	// ordered from ancestors to descendants
+	ignorePatterns []gitignore.Pattern
+	loadedDirs     map[string]bool
+}
+
+func NewFilter(config FilterConfig) (*Filter, error) {
+	include, err := parsePatterns(config.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := parsePatterns(config.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &Filter{
+		config:     config,
+		include:    include,
+		exclude:    exclude,
+		loadedDirs: map[string]bool{},
+	}
+
+	if config.DefaultExcludes {
+		filter.defaultExcludes, _ = parsePatterns(DefaultExcludes)
+	}
+
+	return filter, nil
+}
+
+// Excluded checks if a slash separated path relative to the source path is excluded.
+// Include patterns apply only to files so that directories are always traversed.
+func (f *Filter) Excluded(path string, isDir bool) bool {
+	parts := strings.Split(path, "/")
+
+	if result := matchPatterns(f.exclude, parts, isDir); result != gitignore.NoMatch {
+		return result == gitignore.Exclude
+	}
+
+	if f.config.IgnoreFiles {
+		f.loadIgnoreFiles(parts[:len(parts)-1])
+
+		if result := matchPatterns(f.ignorePatterns, parts, isDir); result != gitignore.NoMatch {
+			return result == gitignore.Exclude
+		}
+	}
+
+	if matchPatterns(f.defaultExcludes, parts, isDir) == gitignore.Exclude {
+		return true
+	}
+
+	if !isDir && len(f.include) > 0 {
+		return matchPatterns(f.include, parts, isDir) != gitignore.Exclude
+	}
+
+	return false
+}
+
+// loadIgnoreFiles reads the ignore files of a directory and its ancestors,
+// unless already loaded
+func (f *Filter) loadIgnoreFiles(dir []string) {
+	for i := 0; i <= len(dir); i++ {
+		domain := dir[:i]
+
+		key := strings.Join(domain, "/")
+		if f.loadedDirs[key] {
+			continue
+		}
+
+		f.loadedDirs[key] = true
+
+		for _, ignoreFile := range IgnoreFiles {
+			ignoreFilePath := filepath.Join(f.config.SourcePath, filepath.FromSlash(key), ignoreFile)
+
+			patterns, err := readIgnoreFile(ignoreFilePath, domain)
+			if err != nil {
+				log.Warnf("Failed to read ignore file %s: %v", ignoreFilePath, err)
+				continue
+			}
+
+			f.ignorePatterns = append(f.ignorePatterns, patterns...)
+		}
+	}
+}
+
+func readIgnoreFile(path string, domain []string) ([]gitignore.Pattern, error) {
+	fd, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := fd.Close(); err != nil {
+			log.Errorf("Failed to close file %s: %v", path, err)
+		}
+	}()
+
+	patterns := []gitignore.Pattern{}
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns, scanner.Err()
+}
+
+func parsePatterns(patterns []string) ([]gitignore.Pattern, error) {
+	parsed := make([]gitignore.Pattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("%w: empty pattern", ErrInvalidPattern)
+		}
+
+		if _, err := filepath.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPattern, pattern, err)
+		}
+
+		parsed = append(parsed, gitignore.ParsePattern(pattern, nil))
+	}
+
+	return parsed, nil
+}
+
+// matchPatterns returns the result of the last matching pattern like git does
+func matchPatterns(patterns []gitignore.Pattern, path []string, isDir bool) gitignore.MatchResult {
+	for i := len(patterns) - 1; i >= 0; i-- {
+		if result := patterns[i].Match(path, isDir); result != gitignore.NoMatch {
+			return result
+		}
+	}
+
+	return gitignore.NoMatch
+}
@@ -0,0 +1,167 @@
+package pathfilter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterExcluded(t *testing.T) {
+	sourcePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, ".gitignore"), []byte("# generated code\ngen/\n*.pb.go\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(sourcePath, "app"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "app", ".xbomignore"), []byte("mocks/\n!vendor/\n"), 0o644))
+
+	cases := []struct {
+		name     string
+		config   FilterConfig
+		path     string
+		isDir    bool
+		excluded bool
+	}{
+		{
+			name:     "nothing excluded without config",
+			config:   FilterConfig{},
+			path:     "node_modules",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "default exclude directory",
+			config:   FilterConfig{DefaultExcludes: true},
+			path:     "web/node_modules",
+			isDir:    true,
+			excluded: true,
+		},
+		{
+			name:     "default exclude build output at the root",
+			config:   FilterConfig{DefaultExcludes: true},
+			path:     "dist",
+			isDir:    true,
+			excluded: true,
+		},
+		{
+			name:     "default exclude build output only at the root",
+			config:   FilterConfig{DefaultExcludes: true},
+			path:     "tools/build",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "default exclude does not match test data",
+			config:   FilterConfig{DefaultExcludes: true},
+			path:     "pkg/testdata",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "default exclude does not match files",
+			config:   FilterConfig{DefaultExcludes: true},
+			path:     "build",
+			isDir:    false,
+			excluded: false,
+		},
+		{
+			name:     "gitignore pattern",
+			config:   FilterConfig{IgnoreFiles: true},
+			path:     "api/service.pb.go",
+			excluded: true,
+		},
+		{
+			name:     "gitignore directory pattern",
+			config:   FilterConfig{IgnoreFiles: true},
+			path:     "gen",
+			isDir:    true,
+			excluded: true,
+		},
+		{
+			name:     "ignore files not honored when disabled",
+			config:   FilterConfig{IgnoreFiles: false},
+			path:     "gen",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "xbomignore in subdirectory",
+			config:   FilterConfig{IgnoreFiles: true},
+			path:     "app/mocks",
+			isDir:    true,
+			excluded: true,
+		},
+		{
+			name:     "xbomignore applies only to its directory",
+			config:   FilterConfig{IgnoreFiles: true},
+			path:     "mocks",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "ignore file overrides default excludes",
+			config:   FilterConfig{IgnoreFiles: true, DefaultExcludes: true},
+			path:     "app/vendor",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "exclude pattern",
+			config:   FilterConfig{Exclude: []string{"test/fixtures/"}},
+			path:     "test/fixtures",
+			isDir:    true,
+			excluded: true,
+		},
+		{
+			name:     "exclude pattern overrides ignore files",
+			config:   FilterConfig{IgnoreFiles: true, Exclude: []string{"!gen/"}},
+			path:     "gen",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "include pattern matches file",
+			config:   FilterConfig{Include: []string{"src/**"}},
+			path:     "src/app/main.py",
+			excluded: false,
+		},
+		{
+			name:     "include pattern does not match file",
+			config:   FilterConfig{Include: []string{"src/**"}},
+			path:     "scripts/build.py",
+			excluded: true,
+		},
+		{
+			name:     "include pattern does not exclude directories",
+			config:   FilterConfig{Include: []string{"src/**"}},
+			path:     "scripts",
+			isDir:    true,
+			excluded: false,
+		},
+		{
+			name:     "include extension pattern",
+			config:   FilterConfig{Include: []string{"*.py"}},
+			path:     "app/main.py",
+			excluded: false,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.SourcePath = sourcePath
+
+			filter, err := NewFilter(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.excluded, filter.Excluded(test.path, test.isDir))
+		})
+	}
+}
+
+func TestNewFilterInvalidPattern(t *testing.T) {
+	_, err := NewFilter(FilterConfig{Exclude: []string{"[a-"}})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+
+	_, err = NewFilter(FilterConfig{Include: []string{" "}})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
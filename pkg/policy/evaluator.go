@@ -0,0 +1,190 @@
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+type EvaluatorConfig struct {
+	// SourcePath is the scanned directory. File paths are
+	// matched relative to this path.
+	SourcePath string
+}
+
+// Violation is a signature match for which a rule evaluated to true
+type Violation struct {
+	Rule        Rule
+	SignatureID string
+	FilePath    string
+	Language    string
+
+	// Line of the first evidence, 1-based. Zero if unknown.
+	Line int
+}
+
+type compiledRule struct {
+	rule    Rule
+	program cel.Program
+}
+
+type Evaluator struct {
+	config EvaluatorConfig
+	rules  []compiledRule
+}
+
+// NewEvaluator compiles the rules of a policy. Compilation errors, including
+// expressions not returning a bool, are reported with the rule name.
+func NewEvaluator(policy *Policy, config EvaluatorConfig) (*Evaluator, error) {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Variable("signature", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("file", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("language", cel.StringType),
+		cel.Variable("conditions", cel.ListType(cel.MapType(cel.StringType, cel.StringType))),
+		cel.Variable("diff_status", cel.StringType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	rules := make([]compiledRule, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		ast, issues := env.Compile(rule.Match)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrCompileRule, rule.Name, issues.Err())
+		}
+
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("%w %s: expression must return bool, got %s",
+				ErrCompileRule, rule.Name, ast.OutputType())
+		}
+
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrCompileRule, rule.Name, err)
+		}
+
+		rules = append(rules, compiledRule{rule: rule, program: program})
+	}
+
+	return &Evaluator{
+		config: config,
+		rules:  rules,
+	}, nil
+}
+
+// Evaluate runs all the rules over each signature match in the findings.
+// Violations are sorted by rule, signature and file for stable output.
+func (e *Evaluator) Evaluate(findings *common.CodeAnalysisFindings) ([]Violation, error) {
+	violations := []Violation{}
+
+	for _, signatureMatchResults := range findings.SignatureWiseMatchResults {
+		for _, signatureMatchResult := range signatureMatchResults {
+			activation := e.activation(signatureMatchResult)
+
+			for _, rule := range e.rules {
+				out, _, err := rule.program.Eval(activation)
+				if err != nil {
+					return nil, fmt.Errorf("%w %s: %w", ErrEvaluateRule, rule.rule.Name, err)
+				}
+
+				violated, ok := out.Value().(bool)
+				if !ok {
+					return nil, fmt.Errorf("%w %s: expression did not return bool", ErrEvaluateRule, rule.rule.Name)
+				}
+
+				if violated {
+					violations = append(violations, Violation{
+						Rule:        rule.rule,
+						SignatureID: signatureMatchResult.MatchedSignature.GetId(),
+						FilePath:    activation["file"].(map[string]string)["path"],
+						Language:    string(signatureMatchResult.MatchedLanguageCode),
+						Line:        firstEvidenceLine(signatureMatchResult),
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Rule.Name != b.Rule.Name {
+			return a.Rule.Name < b.Rule.Name
+		}
+		if a.SignatureID != b.SignatureID {
+			return a.SignatureID < b.SignatureID
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+
+	return violations, nil
+}
+
+func (e *Evaluator) activation(result common.EnrichedSignatureMatchResult) map[string]any {
+	signature := result.MatchedSignature
+
+	tags := signature.GetTags()
+	if tags == nil {
+		tags = []string{}
+	}
+
+	conditions := []map[string]string{}
+	for _, condition := range result.MatchedConditions {
+		if condition.Condition == nil {
+			continue
+		}
+
+		conditions = append(conditions, map[string]string{
+			"type":  condition.Condition.Type,
+			"value": condition.Condition.Value,
+		})
+	}
+
+	return map[string]any{
+		"signature": map[string]any{
+			"id":          signature.GetId(),
+			"description": signature.GetDescription(),
+			"vendor":      signature.GetVendor(),
+			"product":     signature.GetProduct(),
+			"service":     signature.GetService(),
+			"tags":        tags,
+		},
+		"file": map[string]string{
+			"path": e.relativePath(result.FilePath),
+			"name": filepath.Base(result.FilePath),
+		},
+		"language":    string(result.MatchedLanguageCode),
+		"conditions":  conditions,
+		"diff_status": string(result.DiffStatus),
+	}
+}
+
+// relativePath returns the slash separated path of a file relative
+// to the source path, falling back to the path as is
+func (e *Evaluator) relativePath(filePath string) string {
+	if relPath, ok := common.RelativeSourcePath(e.config.SourcePath, filePath); ok {
+		return relPath
+	}
+
+	return filepath.ToSlash(filePath)
+}
+
+func firstEvidenceLine(result common.EnrichedSignatureMatchResult) int {
+	for i, condition := range result.MatchedConditions {
+		for j := range condition.Evidences {
+			if metadata := result.Metadata(i, j); metadata.CallerIdentifierMetadata != nil {
+				return int(metadata.CallerIdentifierMetadata.StartLine) + 1
+			}
+		}
+	}
+
+	return 0
+}
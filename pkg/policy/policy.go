@@ -0,0 +1,98 @@
+// Package policy implements policy evaluation over code analysis findings.
+// A policy is a set of rules with CEL expressions. A rule is violated when
+// its expression evaluates to true for a signature match.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrLoadPolicy      = errors.New("failed to load policy")
+	ErrInvalidPolicy   = errors.New("invalid policy")
+	ErrCompileRule     = errors.New("failed to compile policy rule")
+	ErrEvaluateRule    = errors.New("failed to evaluate policy rule")
+	ErrPolicyViolation = errors.New("policy violation")
+)
+
+// Policy is a collection of rules loaded from a policy file
+//
+// Example:
+// ```
+// version: 0.1
+// rules:
+//   - name: no-openai-outside-ai-service
+//     description: "OpenAI must only be used by the AI service"
+//     match: signature.id.startsWith("openai.") && !file.path.startsWith("services/ai/")
+//
+// ```
+type Policy struct {
+	Version string `yaml:"version"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule describes a disallowed usage. The `match` expression is a CEL
+// expression evaluated for each signature match and must return a bool.
+// The rule is violated when the expression evaluates to true.
+//
+// The following variables are available to the expression:
+//   - `signature` - map with `id`, `description`, `vendor`, `product`, `service` and `tags`
+//   - `file` - map with `path` relative to the scanned directory and `name` of the file
+//   - `language` - language of the file
+//   - `conditions` - list of matched conditions, each a map with `type` and `value`
+type Rule struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Match       string `yaml:"match"`
+}
+
+// LoadPolicy reads and validates a policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadPolicy, err)
+	}
+
+	var policy Policy
+	err = yaml.Unmarshal(data, &policy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLoadPolicy, path, err)
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Validate checks that the policy has rules with unique names and expressions
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("%w: no rules defined", ErrInvalidPolicy)
+	}
+
+	ruleNames := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("%w: rule %d has no name", ErrInvalidPolicy, i)
+		}
+
+		if rule.Match == "" {
+			return fmt.Errorf("%w: rule %s has no match expression", ErrInvalidPolicy, rule.Name)
+		}
+
+		if ruleNames[rule.Name] {
+			return fmt.Errorf("%w: duplicate rule - %s", ErrInvalidPolicy, rule.Name)
+		}
+
+		ruleNames[rule.Name] = true
+	}
+
+	return nil
+}
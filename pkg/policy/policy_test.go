@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr error
+	}{
+		{
+			name: "valid policy",
+			content: `version: 0.1
+rules:
+  - name: no-openai
+    match: signature.id.startsWith("openai.")
+`,
+		},
+		{
+			name:      "no rules",
+			content:   "version: 0.1\n",
+			expectErr: ErrInvalidPolicy,
+		},
+		{
+			name: "rule without expression",
+			content: `rules:
+  - name: no-openai
+`,
+			expectErr: ErrInvalidPolicy,
+		},
+		{
+			name: "duplicate rules",
+			content: `rules:
+  - name: no-openai
+    match: "true"
+  - name: no-openai
+    match: "false"
+`,
+			expectErr: ErrInvalidPolicy,
+		},
+		{
+			name:      "malformed yaml",
+			content:   "rules: [",
+			expectErr: ErrLoadPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := LoadPolicy(writePolicyFile(t, tt.content))
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, policy.Rules, 1)
+		})
+	}
+}
+
+func TestNewEvaluatorCompileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		match string
+	}{
+		{name: "syntax error", match: "signature.id =="},
+		{name: "unknown variable", match: "unknown == 1"},
+		{name: "non bool expression", match: "signature.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEvaluator(&Policy{Rules: []Rule{{Name: "rule", Match: tt.match}}}, EvaluatorConfig{})
+			assert.ErrorIs(t, err, ErrCompileRule)
+		})
+	}
+}
+
+func testPolicyFindings() *common.CodeAnalysisFindings {
+	matchResult := func(filePath string, signature *callgraphv1.Signature, language core.LanguageCode, value string) common.EnrichedSignatureMatchResult {
+		return common.EnrichedSignatureMatchResult{
+			SignatureMatchResult: callgraph.SignatureMatchResult{
+				FilePath:            filePath,
+				MatchedSignature:    signature,
+				MatchedLanguageCode: language,
+				MatchedConditions: []callgraph.MatchedCondition{
+					{
+						Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+							Type:  "call",
+							Value: value,
+						},
+						Evidences: []callgraph.MatchedEvidence{{}},
+					},
+				},
+			},
+		}
+	}
+
+	openaiSignature := &callgraphv1.Signature{Id: "openai.client", Vendor: "OpenAI", Tags: []string{"ai", "llm"}}
+	md5Signature := &callgraphv1.Signature{Id: "cryptography.hash.md5", Tags: []string{"cryptography", "hash"}}
+
+	return &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"openai.client": {
+				matchResult("/src/services/ai/main.py", openaiSignature, core.LanguageCodePython, "openai.*"),
+				matchResult("/src/services/billing/main.py", openaiSignature, core.LanguageCodePython, "openai.*"),
+			},
+			"cryptography.hash.md5": {
+				matchResult("/src/lib/checksum.py", md5Signature, core.LanguageCodePython, "hashlib.md5"),
+			},
+		},
+	}
+}
+
+func TestEvaluatorEvaluate(t *testing.T) {
+	policy := &Policy{
+		Rules: []Rule{
+			{
+				Name:        "openai-only-in-ai-service",
+				Description: "OpenAI must only be used by the AI service",
+				Match:       `signature.id.startsWith("openai.") && !file.path.startsWith("services/ai/")`,
+			},
+			{
+				Name:  "no-md5",
+				Match: `signature.id.matches("^cryptography\\..*md5") || conditions.exists(c, c.value == "hashlib.md5")`,
+			},
+			{
+				Name:  "no-go",
+				Match: `language == "go"`,
+			},
+		},
+	}
+
+	evaluator, err := NewEvaluator(policy, EvaluatorConfig{SourcePath: "/src"})
+	require.NoError(t, err)
+
+	violations, err := evaluator.Evaluate(testPolicyFindings())
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+
+	assert.Equal(t, "no-md5", violations[0].Rule.Name)
+	assert.Equal(t, "cryptography.hash.md5", violations[0].SignatureID)
+	assert.Equal(t, "lib/checksum.py", violations[0].FilePath)
+
+	assert.Equal(t, "openai-only-in-ai-service", violations[1].Rule.Name)
+	assert.Equal(t, "openai.client", violations[1].SignatureID)
+	assert.Equal(t, "services/billing/main.py", violations[1].FilePath)
+	assert.Equal(t, "python", violations[1].Language)
+	assert.Equal(t, 0, violations[1].Line)
+}
+
+func TestEvaluatorEvaluateTags(t *testing.T) {
+	evaluator, err := NewEvaluator(&Policy{
+		Rules: []Rule{{Name: "no-llm", Match: `"llm" in signature.tags && file.name == "main.py"`}},
+	}, EvaluatorConfig{SourcePath: "/src"})
+	require.NoError(t, err)
+
+	violations, err := evaluator.Evaluate(testPolicyFindings())
+	require.NoError(t, err)
+	assert.Len(t, violations, 2)
+}
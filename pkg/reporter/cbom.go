@@ -0,0 +1,311 @@
+package reporter
+
+import (
+	"slices"
+	"strings"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// cryptoTags are the signature tags identifying cryptographic assets
+var cryptoTags = []string{"cryptography", "crypto", "hash", "encryption"}
+
+// cryptoAlgorithm describes a cryptographic algorithm identified by the tags
+// or the service of signatures
+type cryptoAlgorithm struct {
+	name                   string
+	primitive              cdx.CryptoPrimitive
+	parameterSetIdentifier string
+	curve                  string
+	functions              []cdx.CryptoFunction
+
+	// nistQuantumSecurityLevel is nil when it depends on parameters not
+	// known from the signature, such as the key size
+	nistQuantumSecurityLevel *int
+}
+
+func hashAlgorithm(name, parameterSetIdentifier string, nistQuantumSecurityLevel int) cryptoAlgorithm {
+	return cryptoAlgorithm{
+		name:                     name,
+		primitive:                cdx.CryptoPrimitiveHash,
+		parameterSetIdentifier:   parameterSetIdentifier,
+		functions:                []cdx.CryptoFunction{cdx.CryptoFunctionDigest},
+		nistQuantumSecurityLevel: utils.PtrTo(nistQuantumSecurityLevel),
+	}
+}
+
+func kdfAlgorithm(name string) cryptoAlgorithm {
+	return cryptoAlgorithm{
+		name:      name,
+		primitive: cdx.CryptoPrimitiveKDF,
+		functions: []cdx.CryptoFunction{cdx.CryptoFunctionKeyderive},
+	}
+}
+
+// knownCryptoAlgorithms maps the normalized identifiers of algorithms, as used in
+// signature tags and services, to the algorithms. Quantum security levels of hash
+// functions are those of their collision resistance. Algorithms broken classically
+// or by quantum computers have level 0.
+var knownCryptoAlgorithms = map[string]cryptoAlgorithm{
+	"md2":     hashAlgorithm("MD2", "128", 0),
+	"md5":     hashAlgorithm("MD5", "128", 0),
+	"sha1":    hashAlgorithm("SHA-1", "160", 0),
+	"sha224":  hashAlgorithm("SHA-224", "224", 0),
+	"sha256":  hashAlgorithm("SHA-256", "256", 2),
+	"sha384":  hashAlgorithm("SHA-384", "384", 4),
+	"sha512":  hashAlgorithm("SHA-512", "512", 5),
+	"sha3224": hashAlgorithm("SHA3-224", "224", 0),
+	"sha3256": hashAlgorithm("SHA3-256", "256", 2),
+	"sha3384": hashAlgorithm("SHA3-384", "384", 4),
+	"sha3512": hashAlgorithm("SHA3-512", "512", 5),
+	"aes": {
+		name:      "AES",
+		primitive: cdx.CryptoPrimitiveBlockCipher,
+		functions: []cdx.CryptoFunction{cdx.CryptoFunctionEncrypt, cdx.CryptoFunctionDecrypt},
+	},
+	"des": {
+		name:                     "DES",
+		primitive:                cdx.CryptoPrimitiveBlockCipher,
+		functions:                []cdx.CryptoFunction{cdx.CryptoFunctionEncrypt, cdx.CryptoFunctionDecrypt},
+		nistQuantumSecurityLevel: utils.PtrTo(0),
+	},
+	"rsa": {
+		name:      "RSA",
+		primitive: cdx.CryptoPrimitivePKE,
+		functions: []cdx.CryptoFunction{
+			cdx.CryptoFunctionKeygen, cdx.CryptoFunctionEncrypt, cdx.CryptoFunctionDecrypt,
+			cdx.CryptoFunctionSign, cdx.CryptoFunctionVerify,
+		},
+		nistQuantumSecurityLevel: utils.PtrTo(0),
+	},
+	"ecdsa": {
+		name:      "ECDSA",
+		primitive: cdx.CryptoPrimitiveSignature,
+		functions: []cdx.CryptoFunction{
+			cdx.CryptoFunctionKeygen, cdx.CryptoFunctionSign, cdx.CryptoFunctionVerify,
+		},
+		nistQuantumSecurityLevel: utils.PtrTo(0),
+	},
+	"ed25519": {
+		name:      "Ed25519",
+		primitive: cdx.CryptoPrimitiveSignature,
+		curve:     "Ed25519",
+		functions: []cdx.CryptoFunction{
+			cdx.CryptoFunctionKeygen, cdx.CryptoFunctionSign, cdx.CryptoFunctionVerify,
+		},
+		nistQuantumSecurityLevel: utils.PtrTo(0),
+	},
+	"hmac": {
+		name:      "HMAC",
+		primitive: cdx.CryptoPrimitiveMAC,
+		functions: []cdx.CryptoFunction{cdx.CryptoFunctionTag},
+	},
+	"pbkdf2": kdfAlgorithm("PBKDF2"),
+	"scrypt": kdfAlgorithm("scrypt"),
+	"bcrypt": kdfAlgorithm("bcrypt"),
+	"argon2": kdfAlgorithm("Argon2"),
+	"random": {
+		name:      "CSPRNG",
+		primitive: cdx.CryptoPrimitiveDRBG,
+		functions: []cdx.CryptoFunction{cdx.CryptoFunctionGenerate},
+	},
+}
+
+// genericCryptoPrimitives maps the tags of signatures matching families
+// of algorithms to their primitive
+var genericCryptoPrimitives = []struct {
+	tag       string
+	primitive cdx.CryptoPrimitive
+	functions []cdx.CryptoFunction
+}{
+	{"hash", cdx.CryptoPrimitiveHash, []cdx.CryptoFunction{cdx.CryptoFunctionDigest}},
+	{"signing", cdx.CryptoPrimitiveSignature, []cdx.CryptoFunction{cdx.CryptoFunctionSign, cdx.CryptoFunctionVerify}},
+	{"kdf", cdx.CryptoPrimitiveKDF, []cdx.CryptoFunction{cdx.CryptoFunctionKeyderive}},
+	{"encryption", cdx.CryptoPrimitiveUnknown, []cdx.CryptoFunction{cdx.CryptoFunctionEncrypt, cdx.CryptoFunctionDecrypt}},
+}
+
+// Block cipher modes and paddings identified in the values of matched conditions
+var (
+	cryptoAlgorithmModes = []cdx.CryptoAlgorithmMode{
+		cdx.CryptoAlgorithmModeGCM,
+		cdx.CryptoAlgorithmModeCBC,
+		cdx.CryptoAlgorithmModeECB,
+		cdx.CryptoAlgorithmModeCCM,
+		cdx.CryptoAlgorithmModeCFB,
+		cdx.CryptoAlgorithmModeOFB,
+		cdx.CryptoAlgorithmModeCTR,
+	}
+
+	cryptoPaddings = map[string]cdx.CryptoPadding{
+		"oaep":     cdx.CryptoPaddingOAEP,
+		"pkcs1v15": cdx.CryptoPaddingPKCS1v15,
+		"pkcs1_15": cdx.CryptoPaddingPKCS1v15,
+		"pkcs7":    cdx.CryptoPaddingPKCS7,
+		"pkcs5":    cdx.CryptoPaddingPKCS5,
+	}
+)
+
+// isCryptoSignature checks if the signature matches cryptographic assets
+func isCryptoSignature(signature *callgraphv1.Signature) bool {
+	for _, tag := range signature.GetTags() {
+		if slices.Contains(cryptoTags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeCryptoIdentifier makes identifiers such as SHA-256 and sha256 equal
+func normalizeCryptoIdentifier(identifier string) string {
+	return strings.ReplaceAll(strings.ToLower(identifier), "-", "")
+}
+
+// cryptoIdentifiers returns the normalized identifiers of the algorithms a
+// signature may refer to, from its tags and the words of its service
+func cryptoIdentifiers(signature *callgraphv1.Signature) []string {
+	identifiers := []string{}
+	for _, tag := range signature.GetTags() {
+		identifiers = append(identifiers, normalizeCryptoIdentifier(tag))
+	}
+
+	words := strings.FieldsFunc(signature.GetService(), func(r rune) bool {
+		return r == ' ' || r == '/'
+	})
+
+	for _, word := range words {
+		identifiers = append(identifiers, normalizeCryptoIdentifier(word))
+	}
+
+	return identifiers
+}
+
+// cryptoComponentName returns the name of the algorithm of a signature
+// when known, else the name of the signature service
+func cryptoComponentName(signature *callgraphv1.Signature) string {
+	for _, identifier := range cryptoIdentifiers(signature) {
+		if algorithm, ok := knownCryptoAlgorithms[identifier]; ok {
+			return algorithm.name
+		}
+	}
+
+	return signature.GetService()
+}
+
+// cryptoProperties creates the CycloneDX crypto properties of a signature. Block
+// cipher modes and paddings are included only when the matched conditions identify
+// a single one, since the properties describe all occurrences.
+func cryptoProperties(signature *callgraphv1.Signature,
+	matchResults []common.EnrichedSignatureMatchResult,
+) *cdx.CryptoProperties {
+	tags := signature.GetTags()
+
+	switch {
+	case slices.Contains(tags, "x509") || slices.Contains(tags, "certificate"):
+		return &cdx.CryptoProperties{
+			AssetType: cdx.CryptoAssetTypeCertificate,
+			CertificateProperties: &cdx.CertificateProperties{
+				CertificateFormat: "X.509",
+			},
+		}
+	case slices.Contains(tags, "jwt") || slices.Contains(tags, "token"):
+		return &cdx.CryptoProperties{
+			AssetType: cdx.CryptoAssetTypeRelatedCryptoMaterial,
+			RelatedCryptoMaterialProperties: &cdx.RelatedCryptoMaterialProperties{
+				Type: cdx.RelatedCryptoMaterialTypeToken,
+			},
+		}
+	case slices.Contains(tags, "keys"):
+		return &cdx.CryptoProperties{
+			AssetType: cdx.CryptoAssetTypeRelatedCryptoMaterial,
+			RelatedCryptoMaterialProperties: &cdx.RelatedCryptoMaterialProperties{
+				Type: cdx.RelatedCryptoMaterialTypeKey,
+			},
+		}
+	}
+
+	algorithmProperties := &cdx.CryptoAlgorithmProperties{
+		Primitive: cdx.CryptoPrimitiveUnknown,
+	}
+
+	algorithm, known := cryptoAlgorithm{}, false
+	for _, identifier := range cryptoIdentifiers(signature) {
+		if algorithm, known = knownCryptoAlgorithms[identifier]; known {
+			break
+		}
+	}
+
+	if known {
+		algorithmProperties.Primitive = algorithm.primitive
+		algorithmProperties.ParameterSetIdentifier = algorithm.parameterSetIdentifier
+		algorithmProperties.Curve = algorithm.curve
+		algorithmProperties.CryptoFunctions = utils.PtrTo(algorithm.functions)
+		algorithmProperties.NistQuantumSecurityLevel = algorithm.nistQuantumSecurityLevel
+	} else {
+		for _, generic := range genericCryptoPrimitives {
+			if slices.Contains(tags, generic.tag) {
+				algorithmProperties.Primitive = generic.primitive
+				algorithmProperties.CryptoFunctions = utils.PtrTo(generic.functions)
+				break
+			}
+		}
+	}
+
+	conditionValues := matchedConditionValues(matchResults)
+
+	if algorithmProperties.Primitive == cdx.CryptoPrimitiveBlockCipher {
+		modes := []cdx.CryptoAlgorithmMode{}
+		for _, value := range conditionValues {
+			for _, mode := range cryptoAlgorithmModes {
+				if strings.Contains(value, string(mode)) && !slices.Contains(modes, mode) {
+					modes = append(modes, mode)
+				}
+			}
+		}
+
+		if len(modes) == 1 {
+			algorithmProperties.Mode = modes[0]
+		}
+	}
+
+	paddings := []cdx.CryptoPadding{}
+	for _, value := range conditionValues {
+		for keyword, padding := range cryptoPaddings {
+			if strings.Contains(value, keyword) && !slices.Contains(paddings, padding) {
+				paddings = append(paddings, padding)
+			}
+		}
+	}
+
+	if len(paddings) == 1 {
+		algorithmProperties.Padding = paddings[0]
+	}
+
+	return &cdx.CryptoProperties{
+		AssetType:           cdx.CryptoAssetTypeAlgorithm,
+		AlgorithmProperties: algorithmProperties,
+	}
+}
+
+// matchedConditionValues returns the lower case values of the conditions
+// matched by the evidences of the match results
+func matchedConditionValues(matchResults []common.EnrichedSignatureMatchResult) []string {
+	values := []string{}
+	for _, matchResult := range matchResults {
+		for _, condition := range matchResult.MatchedConditions {
+			if len(condition.Evidences) == 0 || condition.Condition == nil {
+				continue
+			}
+
+			value := strings.ToLower(condition.Condition.GetValue())
+			if !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"regexp"
 	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/uuid"
 	"github.com/safedep/dry/log"
@@ -26,6 +29,22 @@ type CycloneDXReporterConfig struct {
 	// Unique identifier for this BOM confirming to UUID RFC 4122 standard
 	// If empty, a new UUID will be generated
 	SerialNumber string
+
+	// SourcePath is the scanned directory. Projects within it, identified by their
+	// manifest such as go.mod, are reported as sub-applications of the root component.
+	SourcePath string
+
+	// ServiceMetadata describes the services of signatures tagged saas, paas or iaas,
+	// keyed by signature ID. Services without metadata are reported without endpoints.
+	ServiceMetadata map[string]common.ServiceMetadata
+
+	// ModelMetadata describes the models used through AI signatures, keyed by signature
+	// ID. Models without metadata are reported with the task derived from the tags.
+	ModelMetadata map[string]common.ModelMetadata
+
+	// InputBOM is an existing BOM, such as a dependency SBOM generated by another tool,
+	// to enrich with the detected components instead of creating a new BOM
+	InputBOM *cdx.BOM
 }
 
 type CycloneDXReporter struct {
@@ -34,18 +53,52 @@ type CycloneDXReporter struct {
 	toolComponent       cdx.Component
 	rootComponentBomref string
 	bomEcosystems       map[string]bool
+	detectedComponents  []string
+	dependencies        []dependencyComponent
+	componentProjects   map[string]map[string]bool
+	projectDirs         map[string]bool
+	dependsOn           map[string][]string
+	skippedFiles        []common.SkippedFile
+	failedFiles         []common.FailedFile
 }
 
 var _ Reporter = (*CycloneDXReporter)(nil)
 
+// cdxKnownTags are the signature tags reported as properties of components
+var cdxKnownTags = []string{
+	"ai",
+	"cryptography",
+	"encryption",
+	"hash",
+	"ml",
+	"iaas",
+	"paas",
+	"saas",
+}
+
+// cdxAnnotationBomRefs are the bom-refs of the annotations created by the reporter
+var cdxAnnotationBomRefs = []string{"metadata-annotations", "not-analyzed-annotations", "diagnostics-annotations"}
+
 var cdxUUIDRegexp = regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
 func NewCycloneDXBomReporter(config CycloneDXReporterConfig) (*CycloneDXReporter, error) {
 	bom := cdx.NewBOM()
 	bom.SpecVersion = cdx.SpecVersion1_6
+	if config.InputBOM != nil {
+		bom = config.InputBOM
+	}
+
+	// Set serial number if provided, keep the serial number of the BOM to enrich
+	// as a new version of it, otherwise generate a RFC 4122 UUID
+	if utils.IsEmptyString(config.SerialNumber) && config.InputBOM != nil && bom.SerialNumber != "" {
+		if !cdxUUIDRegexp.MatchString(bom.SerialNumber) {
+			return nil, fmt.Errorf("serial number '%s' of the BOM to enrich does not match RFC 4122 UUID format", bom.SerialNumber)
+		}
+
+		bom.Version = max(bom.Version, 1) + 1
+	} else if utils.IsEmptyString(config.SerialNumber) {
+		bom.Version = 1
 
-	// Set serial number if provided, otherwise generate a RFC 4122 UUID
-	if utils.IsEmptyString(config.SerialNumber) {
 		generatedSerialNumber, err := uuid.NewUUID()
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate UUID for CycloneDX serial number: %v", err)
@@ -57,6 +110,7 @@ func NewCycloneDXBomReporter(config CycloneDXReporterConfig) (*CycloneDXReporter
 			return nil, fmt.Errorf("serial number '%s' does not match RFC 4122 UUID format", config.SerialNumber)
 		}
 
+		bom.Version = 1
 		bom.SerialNumber = config.SerialNumber
 	}
 
@@ -73,11 +127,16 @@ func NewCycloneDXBomReporter(config CycloneDXReporterConfig) (*CycloneDXReporter
 		BOMRef:     config.Tool.Purl,
 	}
 
-	rootComponentBomref := "root-application"
+	if config.InputBOM != nil {
+		prepareInputBom(bom, config, toolComponent)
+
+		return newCycloneDXReporter(config, bom, toolComponent), nil
+	}
+
 	bom.Metadata = &cdx.Metadata{
 		// Define metadata about the main component (the root component which BOM describes)
 		Component: &cdx.Component{
-			BOMRef:     rootComponentBomref,
+			BOMRef:     "root-application",
 			Type:       cdx.ComponentTypeApplication,
 			Name:       config.ApplicationComponentName,
 			Components: utils.PtrTo([]cdx.Component{}),
@@ -94,13 +153,21 @@ func NewCycloneDXBomReporter(config CycloneDXReporterConfig) (*CycloneDXReporter
 	bom.Dependencies = utils.PtrTo([]cdx.Dependency{})
 	bom.Services = utils.PtrTo([]cdx.Service{})
 
+	return newCycloneDXReporter(config, bom, toolComponent), nil
+}
+
+func newCycloneDXReporter(config CycloneDXReporterConfig, bom *cdx.BOM, toolComponent cdx.Component) *CycloneDXReporter {
 	return &CycloneDXReporter{
 		config:              config,
 		bom:                 bom,
 		toolComponent:       toolComponent,
-		rootComponentBomref: rootComponentBomref,
+		rootComponentBomref: bom.Metadata.Component.BOMRef,
 		bomEcosystems:       map[string]bool{},
-	}, nil
+		dependencies:        dependencyComponents(bom),
+		componentProjects:   map[string]map[string]bool{},
+		projectDirs:         map[string]bool{},
+		dependsOn:           map[string][]string{},
+	}
 }
 
 func (c *CycloneDXReporter) Name() string {
@@ -108,32 +175,41 @@ func (c *CycloneDXReporter) Name() string {
 }
 
 func (c *CycloneDXReporter) RecordCodeAnalysisFindings(findings *common.CodeAnalysisFindings) error {
-	for signatureId, signatureMatchResults := range findings.SignatureWiseMatchResults {
-		if len(signatureMatchResults) == 0 {
-			continue
+	signatureIds := []string{}
+	for signatureId := range findings.SignatureWiseMatchResults {
+		signatureIds = append(signatureIds, signatureId)
+	}
+	for signatureId := range findings.SuppressedMatchResults {
+		if _, ok := findings.SignatureWiseMatchResults[signatureId]; !ok {
+			signatureIds = append(signatureIds, signatureId)
 		}
-		signature := signatureMatchResults[0].MatchedSignature
+	}
 
-		occurrences := &[]cdx.EvidenceOccurrence{}
-		for _, signatureMatchResult := range signatureMatchResults {
-			for _, condition := range signatureMatchResult.MatchedConditions {
-				for _, evidence := range condition.Evidences {
-					metadata := evidence.Metadata(signatureMatchResult.TreeData)
-					evidenceOccurrence := cdx.EvidenceOccurrence{
-						Location:          signatureMatchResult.FilePath,
-						AdditionalContext: metadata.CalleeNamespace,
-					}
+	// Signatures are sorted so that components merged from multiple
+	// signatures, such as models, are deterministic
+	slices.Sort(signatureIds)
 
-					if metadata.CallerIdentifierMetadata != nil {
-						evidenceOccurrence.Line = utils.PtrTo(int(metadata.CallerIdentifierMetadata.StartLine + 1))
-						evidenceOccurrence.Offset = utils.PtrTo(int(metadata.CallerIdentifierMetadata.StartColumn + 1))
-					}
+	for _, signatureId := range signatureIds {
+		signatureMatchResults := findings.SignatureWiseMatchResults[signatureId]
+		suppressedMatchResults := findings.SuppressedMatchResults[signatureId]
+		if len(signatureMatchResults) == 0 && len(suppressedMatchResults) == 0 {
+			continue
+		}
 
-					*occurrences = append(*occurrences, evidenceOccurrence)
-				}
-			}
+		var signature *callgraphv1.Signature
+		if len(signatureMatchResults) > 0 {
+			signature = signatureMatchResults[0].MatchedSignature
+		} else {
+			signature = suppressedMatchResults[0].MatchedSignature
 		}
 
+		matchResults := slices.Concat(signatureMatchResults, suppressedMatchResults)
+		occurrences := c.evidenceOccurrences(signatureMatchResults)
+
+		// Suppressed evidences are retained in the BOM for audit purposes
+		suppressedOccurrences := c.evidenceOccurrences(suppressedMatchResults)
+		*occurrences = append(*occurrences, *suppressedOccurrences...)
+
 		component := cdx.Component{
 			BOMRef:      signatureId,
 			Name:        signature.Product + " - " + signature.Service,
@@ -163,27 +239,127 @@ func (c *CycloneDXReporter) RecordCodeAnalysisFindings(findings *common.CodeAnal
 			Properties: &[]cdx.Property{},
 		}
 
+		// Cryptographic assets are described by their crypto properties for CBOM consumers
+		if isCryptoSignature(signature) {
+			component.Type = cdx.ComponentTypeCryptographicAsset
+			component.Name = cryptoComponentName(signature)
+			component.CryptoProperties = cryptoProperties(signature, matchResults)
+		}
+
 		*component.Properties = append(*component.Properties, c.getKnownTaggedProperties(signature.Tags)...)
 
-		*c.bom.Components = append(*c.bom.Components, component)
+		if len(*suppressedOccurrences) > 0 {
+			*component.Properties = append(*component.Properties, cdx.Property{
+				Name:  "suppressed_occurrences",
+				Value: fmt.Sprintf("%d", len(*suppressedOccurrences)),
+			})
+		}
+
+		if len(signatureMatchResults) == 0 {
+			*component.Properties = append(*component.Properties, cdx.Property{
+				Name:  "suppressed",
+				Value: "true",
+			})
+		}
+
+		// Packages already in the BOM to enrich are linked instead of duplicated,
+		// cryptographic assets are not packages and are always added
+		componentBomRef := component.BOMRef
+		if linkedBomRef, ok := c.linkedDependency(matchResults); ok && !isCryptoSignature(signature) {
+			c.mergeIntoComponent(linkedBomRef, component, signature)
+			componentBomRef = linkedBomRef
+		} else {
+			upsertComponent(c.bom.Components, component)
+		}
+
+		if !slices.Contains(c.detectedComponents, componentBomRef) {
+			c.detectedComponents = append(c.detectedComponents, componentBomRef)
+		}
+
+		c.recordComponentProjects(componentBomRef, matchResults)
+
+		// Third party services are listed for SaaS-BOM consumers, the component
+		// used to access the service carries the evidences
+		if isServiceSignature(signature) {
+			service := cdxService(signature, c.config.ServiceMetadata[signatureId],
+				componentBomRef, len(*occurrences))
+
+			*service.Properties = append(*service.Properties, c.getKnownTaggedProperties(signature.Tags)...)
+
+			if len(signatureMatchResults) == 0 {
+				*service.Properties = append(*service.Properties, cdx.Property{
+					Name:  "suppressed",
+					Value: "true",
+				})
+			}
+
+			upsertService(c.bom.Services, service)
+			c.dependsOn[componentBomRef] = append(c.dependsOn[componentBomRef], service.BOMRef)
+		}
+
+		// Models are listed for ML-BOM consumers along with the SDK component
+		// carrying the evidences
+		if model, ok := cdxModelComponent(signature, c.config.ModelMetadata[signatureId],
+			componentBomRef, len(*occurrences)); ok {
+			*model.Properties = append(*model.Properties, c.getKnownTaggedProperties(signature.Tags)...)
+
+			if len(signatureMatchResults) == 0 {
+				*model.Properties = append(*model.Properties, cdx.Property{
+					Name:  "suppressed",
+					Value: "true",
+				})
+			}
+
+			c.addModelComponent(model)
+			c.dependsOn[componentBomRef] = append(c.dependsOn[componentBomRef], model.BOMRef)
+		}
 	}
+
+	c.skippedFiles = append(c.skippedFiles, findings.SkippedFiles...)
+	c.failedFiles = append(c.failedFiles, findings.FailedFiles...)
+
 	return nil
 }
 
-func (c *CycloneDXReporter) getKnownTaggedProperties(tags []string) []cdx.Property {
-	knownTags := []string{
-		"ai",
-		"cryptography",
-		"encryption",
-		"hash",
-		"ml",
-		"iaas",
-		"paas",
-		"saas",
+// evidenceOccurrences creates an occurrence for every evidence in the match results.
+// Occurrences of suppressed match results mention the suppression in their context.
+func (c *CycloneDXReporter) evidenceOccurrences(signatureMatchResults []common.EnrichedSignatureMatchResult) *[]cdx.EvidenceOccurrence {
+	occurrences := &[]cdx.EvidenceOccurrence{}
+	for _, signatureMatchResult := range signatureMatchResults {
+		for i, condition := range signatureMatchResult.MatchedConditions {
+			for j := range condition.Evidences {
+				metadata := signatureMatchResult.Metadata(i, j)
+				evidenceOccurrence := cdx.EvidenceOccurrence{
+					Location:          signatureMatchResult.FilePath,
+					AdditionalContext: metadata.CalleeNamespace,
+				}
+
+				if metadata.CallerIdentifierMetadata != nil {
+					evidenceOccurrence.Line = utils.PtrTo(int(metadata.CallerIdentifierMetadata.StartLine + 1))
+					evidenceOccurrence.Offset = utils.PtrTo(int(metadata.CallerIdentifierMetadata.StartColumn + 1))
+				}
+
+				if suppression := signatureMatchResult.Suppression; suppression != nil {
+					suppressedBy := suppression.Source
+					if suppression.Reason != "" {
+						suppressedBy = fmt.Sprintf("%s - %s", suppression.Source, suppression.Reason)
+					}
+
+					evidenceOccurrence.AdditionalContext = fmt.Sprintf("%s (suppressed: %s)",
+						metadata.CalleeNamespace, suppressedBy)
+				}
+
+				*occurrences = append(*occurrences, evidenceOccurrence)
+			}
+		}
 	}
 
+	return occurrences
+}
+
+func (c *CycloneDXReporter) getKnownTaggedProperties(tags []string) []cdx.Property {
 	properties := []cdx.Property{}
-	for _, tag := range knownTags {
+	for _, tag := range cdxKnownTags {
 		if slices.Contains(tags, tag) {
 			properties = append(properties, cdx.Property{
 				Name:  tag,
@@ -228,9 +404,41 @@ func (r *CycloneDXReporter) finaliseBom() {
 
 	r.bom.Metadata.Timestamp = bomGenerationTime.Format(time.RFC3339)
 
-	r.bom.Annotations = utils.PtrTo([]cdx.Annotation{
-		{
-			BOMRef: "metadata-annotations",
+	r.buildDependencyGraph()
+
+	// Annotations of a BOM being enriched are retained
+	if r.bom.Annotations == nil {
+		r.bom.Annotations = utils.PtrTo([]cdx.Annotation{})
+	}
+
+	// Annotations of an earlier enrichment by the tool are replaced
+	*r.bom.Annotations = slices.DeleteFunc(*r.bom.Annotations, func(annotation cdx.Annotation) bool {
+		return slices.Contains(cdxAnnotationBomRefs, annotation.BOMRef)
+	})
+
+	*r.bom.Annotations = append(*r.bom.Annotations, cdx.Annotation{
+		BOMRef: "metadata-annotations",
+		Subjects: utils.PtrTo([]cdx.BOMReference{
+			cdx.BOMReference(r.rootComponentBomref),
+		}),
+		Annotator: &cdx.Annotator{
+			Component: &r.toolComponent,
+		},
+		Timestamp: bomGenerationTime.Format(time.RFC3339),
+		Text:      fmt.Sprintf("This Software Bill-of-Materials (SBOM) document was created on %s with %s. The data was captured during the build lifecycle phase. The document describes '%s'. It has total %d components.", bomGenerationTime.Format("Monday, January 2, 2006"), r.config.Tool.Name, r.bom.Metadata.Component.Name, len(*r.bom.Components)),
+	})
+
+	// Components used only in files not analyzed are missing from the BOM
+	if len(r.skippedFiles) > 0 {
+		notAnalyzed := make([]string, len(r.skippedFiles))
+		for i, skippedFile := range r.skippedFiles {
+			notAnalyzed[i] = fmt.Sprintf("%s (%s)", skippedFile.Path, skipReasonDescription(skippedFile.Reason))
+		}
+
+		sort.Strings(notAnalyzed)
+
+		*r.bom.Annotations = append(*r.bom.Annotations, cdx.Annotation{
+			BOMRef: "not-analyzed-annotations",
 			Subjects: utils.PtrTo([]cdx.BOMReference{
 				cdx.BOMReference(r.rootComponentBomref),
 			}),
@@ -238,7 +446,31 @@ func (r *CycloneDXReporter) finaliseBom() {
 				Component: &r.toolComponent,
 			},
 			Timestamp: bomGenerationTime.Format(time.RFC3339),
-			Text:      fmt.Sprintf("This Software Bill-of-Materials (SBOM) document was created on %s with %s. The data was captured during the build lifecycle phase. The document describes '%s'. It has total %d components.", bomGenerationTime.Format("Monday, January 2, 2006"), r.config.Tool.Name, r.config.ApplicationComponentName, len(*r.bom.Components)),
-		},
-	})
+			Text: fmt.Sprintf("%d files were not analyzed for exceeding the analysis budgets: %s",
+				len(notAnalyzed), strings.Join(notAnalyzed, ", ")),
+		})
+	}
+
+	// The BOM is incomplete when the analysis of any file failed
+	if len(r.failedFiles) > 0 {
+		diagnostics := make([]string, len(r.failedFiles))
+		for i, failedFile := range r.failedFiles {
+			diagnostics[i] = fmt.Sprintf("%s (%s)", failedFile.Path, failedFile.Err.Error())
+		}
+
+		sort.Strings(diagnostics)
+
+		*r.bom.Annotations = append(*r.bom.Annotations, cdx.Annotation{
+			BOMRef: "diagnostics-annotations",
+			Subjects: utils.PtrTo([]cdx.BOMReference{
+				cdx.BOMReference(r.rootComponentBomref),
+			}),
+			Annotator: &cdx.Annotator{
+				Component: &r.toolComponent,
+			},
+			Timestamp: bomGenerationTime.Format(time.RFC3339),
+			Text: fmt.Sprintf("The analysis of %d files failed, the BOM is incomplete: %s",
+				len(diagnostics), strings.Join(diagnostics, ", ")),
+		})
+	}
 }
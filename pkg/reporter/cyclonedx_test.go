@@ -0,0 +1,805 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	_ "github.com/safedep/xbom/signatures" // Initialize embedded signatures
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cdxTestMatchResult(signature *callgraphv1.Signature, values ...string) common.EnrichedSignatureMatchResult {
+	conditions := []callgraph.MatchedCondition{}
+	for _, value := range values {
+		conditions = append(conditions, callgraph.MatchedCondition{
+			Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{Type: "call", Value: value},
+			Evidences: []callgraph.MatchedEvidence{{}},
+		})
+	}
+
+	return common.EnrichedSignatureMatchResult{
+		SignatureMatchResult: callgraph.SignatureMatchResult{
+			FilePath:            "/src/main.go",
+			MatchedSignature:    signature,
+			MatchedLanguageCode: core.LanguageCodeGo,
+			MatchedConditions:   conditions,
+		},
+	}
+}
+
+func generateTestBOM(t *testing.T, config CycloneDXReporterConfig, findings *common.CodeAnalysisFindings) *cdx.BOM {
+	t.Helper()
+
+	config.Tool = common.ToolMetadata{Name: "xbom", Version: "1.0.0"}
+	config.Path = filepath.Join(t.TempDir(), "bom.cdx.json")
+	config.ApplicationComponentName = "app"
+
+	reporter, err := NewCycloneDXBomReporter(config)
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	fd, err := os.Open(config.Path)
+	require.NoError(t, err)
+	defer fd.Close()
+
+	var bom cdx.BOM
+	require.NoError(t, cdx.NewBOMDecoder(fd, cdx.BOMFileFormatJSON).Decode(&bom))
+
+	return &bom
+}
+
+func testBOMComponents(bom *cdx.BOM) map[string]cdx.Component {
+	components := map[string]cdx.Component{}
+	for _, component := range *bom.Components {
+		components[component.BOMRef] = component
+	}
+
+	return components
+}
+
+func TestCycloneDXReporter_CryptographicAssets(t *testing.T) {
+	aes := &callgraphv1.Signature{
+		Id:      "golang.crypto.aes",
+		Vendor:  "Go",
+		Product: "Standard Library",
+		Service: "AES",
+		Tags:    []string{"crypto", "aes", "encryption", "capability"},
+	}
+	rsa := &callgraphv1.Signature{
+		Id:      "golang.crypto.rsa",
+		Service: "RSA",
+		Tags:    []string{"crypto", "rsa", "encryption", "signing", "capability"},
+	}
+	sha := &callgraphv1.Signature{
+		Id:      "crypto.sha3-256",
+		Product: "Hashing algorithm",
+		Service: "SHA3-256 hash",
+		Tags:    []string{"cryptography", "hash", "sha3"},
+	}
+	hashing := &callgraphv1.Signature{
+		Id:      "python.crypto.hash",
+		Service: "Cryptographic hashing",
+		Tags:    []string{"crypto", "hash", "capability"},
+	}
+	x509 := &callgraphv1.Signature{
+		Id:      "golang.crypto.x509",
+		Service: "X.509 certificates",
+		Tags:    []string{"crypto", "x509", "certificate", "capability"},
+	}
+	openai := &callgraphv1.Signature{
+		Id:      "openai.client",
+		Vendor:  "OpenAI",
+		Product: "OpenAI",
+		Service: "AI client",
+		Tags:    []string{"ai", "llm"},
+	}
+
+	bom := generateTestBOM(t, CycloneDXReporterConfig{}, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			aes.Id: {
+				cdxTestMatchResult(aes, "crypto/aes/NewCipher", "crypto/cipher/NewGCM"),
+			},
+			rsa.Id: {
+				cdxTestMatchResult(rsa, "crypto/rsa/EncryptOAEP"),
+				cdxTestMatchResult(rsa, "crypto/rsa/SignPKCS1v15"),
+			},
+			sha.Id:     {cdxTestMatchResult(sha, "hashlib.sha3_256")},
+			hashing.Id: {cdxTestMatchResult(hashing, "hashlib.new")},
+			x509.Id:    {cdxTestMatchResult(x509, "crypto/x509/ParseCertificate")},
+			openai.Id:  {cdxTestMatchResult(openai, "openai.OpenAI")},
+		},
+	})
+	components := testBOMComponents(bom)
+
+	t.Run("algorithm with mode", func(t *testing.T) {
+		component := components[aes.Id]
+		assert.Equal(t, cdx.ComponentTypeCryptographicAsset, component.Type)
+		assert.Equal(t, "AES", component.Name)
+		require.NotNil(t, component.CryptoProperties)
+		assert.Equal(t, cdx.CryptoAssetTypeAlgorithm, component.CryptoProperties.AssetType)
+
+		algorithm := component.CryptoProperties.AlgorithmProperties
+		require.NotNil(t, algorithm)
+		assert.Equal(t, cdx.CryptoPrimitiveBlockCipher, algorithm.Primitive)
+		assert.Equal(t, cdx.CryptoAlgorithmModeGCM, algorithm.Mode)
+		assert.Nil(t, algorithm.NistQuantumSecurityLevel, "depends on the key size")
+	})
+
+	t.Run("ambiguous padding is omitted", func(t *testing.T) {
+		algorithm := components[rsa.Id].CryptoProperties.AlgorithmProperties
+		require.NotNil(t, algorithm)
+		assert.Equal(t, cdx.CryptoPrimitivePKE, algorithm.Primitive)
+		assert.Empty(t, algorithm.Padding)
+		require.NotNil(t, algorithm.NistQuantumSecurityLevel)
+		assert.Equal(t, 0, *algorithm.NistQuantumSecurityLevel)
+	})
+
+	t.Run("hash with parameter set", func(t *testing.T) {
+		component := components[sha.Id]
+		assert.Equal(t, "SHA3-256", component.Name)
+
+		algorithm := component.CryptoProperties.AlgorithmProperties
+		require.NotNil(t, algorithm)
+		assert.Equal(t, cdx.CryptoPrimitiveHash, algorithm.Primitive)
+		assert.Equal(t, "256", algorithm.ParameterSetIdentifier)
+		assert.Equal(t, []cdx.CryptoFunction{cdx.CryptoFunctionDigest}, *algorithm.CryptoFunctions)
+		require.NotNil(t, algorithm.NistQuantumSecurityLevel)
+		assert.Equal(t, 2, *algorithm.NistQuantumSecurityLevel)
+	})
+
+	t.Run("family of algorithms", func(t *testing.T) {
+		component := components[hashing.Id]
+		assert.Equal(t, "Cryptographic hashing", component.Name)
+
+		algorithm := component.CryptoProperties.AlgorithmProperties
+		require.NotNil(t, algorithm)
+		assert.Equal(t, cdx.CryptoPrimitiveHash, algorithm.Primitive)
+		assert.Empty(t, algorithm.ParameterSetIdentifier)
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		component := components[x509.Id]
+		assert.Equal(t, cdx.CryptoAssetTypeCertificate, component.CryptoProperties.AssetType)
+		assert.Nil(t, component.CryptoProperties.AlgorithmProperties)
+	})
+
+	t.Run("non crypto component", func(t *testing.T) {
+		component := components[openai.Id]
+		assert.Equal(t, cdx.ComponentTypeLibrary, component.Type)
+		assert.Equal(t, "OpenAI - AI client", component.Name)
+		assert.Nil(t, component.CryptoProperties)
+	})
+}
+
+func TestCycloneDXReporter_Services(t *testing.T) {
+	pubsub := &callgraphv1.Signature{
+		Id:          "gcp.pubsub",
+		Description: "Google Cloud Pub/Sub",
+		Vendor:      "Google",
+		Product:     "Google Cloud Platform",
+		Service:     "GCP PubSub",
+		Tags:        []string{"pubsub", "messaging", "paas"},
+	}
+	internal := &callgraphv1.Signature{
+		Id:      "acme.billing",
+		Product: "Billing",
+		Service: "API client",
+		Tags:    []string{"saas"},
+	}
+	aes := &callgraphv1.Signature{
+		Id:      "golang.crypto.aes",
+		Service: "AES",
+		Tags:    []string{"crypto", "encryption"},
+	}
+
+	bom := generateTestBOM(t, CycloneDXReporterConfig{
+		ServiceMetadata: map[string]common.ServiceMetadata{
+			pubsub.Id: {
+				Endpoints: []string{"https://pubsub.googleapis.com"},
+				DataFlows: []common.ServiceDataFlow{{Flow: "outbound", Classification: "messages"}},
+			},
+			internal.Id: {TrustBoundary: utils.PtrTo(false)},
+		},
+	}, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			pubsub.Id: {
+				cdxTestMatchResult(pubsub, "google.cloud.pubsub.PublisherClient"),
+				cdxTestMatchResult(pubsub, "google.cloud.pubsub.SubscriberClient"),
+			},
+			aes.Id: {cdxTestMatchResult(aes, "crypto/aes/NewCipher")},
+		},
+		SuppressedMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			internal.Id: {cdxTestMatchResult(internal, "acme.billing.Client")},
+		},
+	})
+
+	services := map[string]cdx.Service{}
+	for _, service := range *bom.Services {
+		services[service.BOMRef] = service
+	}
+	require.Len(t, services, 2)
+
+	properties := func(service cdx.Service) map[string]string {
+		values := map[string]string{}
+		for _, property := range *service.Properties {
+			values[property.Name] = property.Value
+		}
+
+		return values
+	}
+
+	t.Run("service with metadata", func(t *testing.T) {
+		service := services["service:gcp.pubsub"]
+		require.NotNil(t, service.Provider)
+		assert.Equal(t, "Google", service.Provider.Name)
+		assert.Equal(t, "Google Cloud Platform - GCP PubSub", service.Name)
+		assert.Equal(t, "Google Cloud Pub/Sub", service.Description)
+		assert.Equal(t, []string{"https://pubsub.googleapis.com"}, *service.Endpoints)
+		require.NotNil(t, service.CrossesTrustBoundary)
+		assert.True(t, *service.CrossesTrustBoundary)
+		assert.Equal(t, []cdx.DataClassification{
+			{Flow: cdx.DataFlowOutbound, Classification: "messages"},
+		}, *service.Data)
+		assert.Equal(t, map[string]string{
+			"evidence":    "gcp.pubsub",
+			"occurrences": "2",
+			"paas":        "true",
+		}, properties(service))
+	})
+
+	t.Run("service within the trust boundary", func(t *testing.T) {
+		service := services["service:acme.billing"]
+		assert.Nil(t, service.Provider)
+		assert.Nil(t, service.Endpoints)
+		require.NotNil(t, service.CrossesTrustBoundary)
+		assert.False(t, *service.CrossesTrustBoundary)
+		assert.Equal(t, "true", properties(service)["suppressed"])
+	})
+
+	t.Run("components remain as evidence", func(t *testing.T) {
+		components := testBOMComponents(bom)
+		assert.Contains(t, components, pubsub.Id)
+		assert.Contains(t, components, internal.Id)
+		assert.Contains(t, components, aes.Id)
+
+		assert.Contains(t, *bom.Dependencies, cdx.Dependency{
+			Ref:          pubsub.Id,
+			Dependencies: utils.PtrTo([]string{"service:gcp.pubsub"}),
+		})
+	})
+}
+
+func TestCycloneDXReporter_DependencyGraph(t *testing.T) {
+	sourcePath := t.TempDir()
+	for _, manifest := range []string{"go.mod", "services/api/package.json", "tools/pyproject.toml"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(sourcePath, filepath.Dir(manifest)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(sourcePath, manifest), []byte{}, 0o644))
+	}
+
+	matchResultIn := func(signature *callgraphv1.Signature, filePath string) common.EnrichedSignatureMatchResult {
+		matchResult := cdxTestMatchResult(signature, "call")
+		matchResult.FilePath = filepath.Join(sourcePath, filePath)
+		return matchResult
+	}
+
+	openai := &callgraphv1.Signature{Id: "openai.client", Tags: []string{"saas"}}
+	langchain := &callgraphv1.Signature{Id: "langchain.llm"}
+	crewai := &callgraphv1.Signature{Id: "crewai.agent"}
+
+	bom := generateTestBOM(t, CycloneDXReporterConfig{SourcePath: sourcePath}, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			openai.Id: {
+				matchResultIn(openai, "main.go"),
+				matchResultIn(openai, "services/api/src/index.js"),
+			},
+			langchain.Id: {
+				matchResultIn(langchain, "tools/cli/main.py"),
+				matchResultIn(langchain, "tools/agent.py"),
+			},
+		},
+		SuppressedMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			crewai.Id: {matchResultIn(crewai, "scripts/run.py")},
+		},
+	})
+
+	dependencies := map[string][]string{}
+	for _, dependency := range *bom.Dependencies {
+		require.NotContains(t, dependencies, dependency.Ref, "dependency refs must be unique")
+
+		dependencies[dependency.Ref] = []string{}
+		if dependency.Dependencies != nil {
+			dependencies[dependency.Ref] = *dependency.Dependencies
+		}
+	}
+
+	assert.Equal(t, map[string][]string{
+		"root-application":         {"application:services/api", "application:tools", "crewai.agent", "openai.client"},
+		"application:services/api": {"openai.client"},
+		"application:tools":        {"langchain.llm"},
+		"openai.client":            {"service:openai.client"},
+		"langchain.llm":            {},
+		"crewai.agent":             {},
+	}, dependencies)
+
+	applications := []string{}
+	for _, application := range *bom.Metadata.Component.Components {
+		assert.Equal(t, cdx.ComponentTypeApplication, application.Type)
+		applications = append(applications, application.Name)
+	}
+
+	assert.Equal(t, []string{"services/api", "tools"}, applications)
+}
+
+func TestCycloneDXReporter_EnrichBOM(t *testing.T) {
+	inputBom := cdx.NewBOM()
+	inputBom.SerialNumber = "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"
+	inputBom.Version = 3
+	inputBom.SpecVersion = cdx.SpecVersion1_5
+	inputBom.Metadata = &cdx.Metadata{
+		Component: &cdx.Component{BOMRef: "pkg:npm/shop@1.0.0", Type: cdx.ComponentTypeApplication, Name: "shop"},
+		Tools: &cdx.ToolsChoice{
+			Components: utils.PtrTo([]cdx.Component{{Type: cdx.ComponentTypeApplication, Name: "cdxgen"}}),
+		},
+	}
+	inputBom.Components = utils.PtrTo([]cdx.Component{
+		{BOMRef: "pkg:pypi/openai@1.0.0", PackageURL: "pkg:pypi/openai@1.0.0", Type: cdx.ComponentTypeLibrary, Name: "openai"},
+		{BOMRef: "pubsub-js", PackageURL: "pkg:npm/%40google-cloud/pubsub@4.0.0", Type: cdx.ComponentTypeLibrary, Name: "pubsub"},
+		{BOMRef: "pubsub-py", PackageURL: "pkg:pypi/google-cloud-pubsub@2.0.0", Type: cdx.ComponentTypeLibrary, Name: "google-cloud-pubsub"},
+	})
+	inputBom.Dependencies = utils.PtrTo([]cdx.Dependency{
+		{Ref: "pkg:npm/shop@1.0.0", Dependencies: utils.PtrTo([]string{"pubsub-js"})},
+	})
+
+	matchResultWithCallee := func(signature *callgraphv1.Signature, language core.LanguageCode,
+		calleeNamespace string) common.EnrichedSignatureMatchResult {
+		matchResult := cdxTestMatchResult(signature, "call")
+		matchResult.MatchedLanguageCode = language
+		matchResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{{{CalleeNamespace: calleeNamespace}}}
+		return matchResult
+	}
+
+	openai := &callgraphv1.Signature{Id: "openai.client", Vendor: "OpenAI", Tags: []string{"ai", "saas"}}
+	openaiAsync := &callgraphv1.Signature{Id: "openai.async", Vendor: "OpenAI", Tags: []string{"ai"}}
+	pubsub := &callgraphv1.Signature{Id: "gcp.pubsub", Tags: []string{"paas"}}
+	aes := &callgraphv1.Signature{Id: "golang.crypto.aes", Service: "AES", Tags: []string{"crypto"}}
+
+	bom := generateTestBOM(t, CycloneDXReporterConfig{InputBOM: inputBom}, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			openai.Id:      {matchResultWithCallee(openai, core.LanguageCodePython, "openai//OpenAI")},
+			openaiAsync.Id: {matchResultWithCallee(openaiAsync, core.LanguageCodePython, "openai//AsyncOpenAI")},
+			pubsub.Id: {
+				matchResultWithCallee(pubsub, core.LanguageCodeJavascript, "@google-cloud//pubsub//PubSub"),
+				matchResultWithCallee(pubsub, core.LanguageCodeJavascript, "@google-cloud//pubsub//v1//PublisherClient"),
+				matchResultWithCallee(pubsub, core.LanguageCodePython, "google//cloud//pubsub//PublisherClient"),
+			},
+			aes.Id: {matchResultWithCallee(aes, core.LanguageCodeGo, "crypto//aes//NewCipher")},
+		},
+	})
+
+	t.Run("serial lineage and metadata are kept", func(t *testing.T) {
+		assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom.SerialNumber)
+		assert.Equal(t, 4, bom.Version)
+		assert.Equal(t, cdx.SpecVersion1_6, bom.SpecVersion)
+		assert.Equal(t, "shop", bom.Metadata.Component.Name)
+
+		tools := []string{}
+		for _, tool := range *bom.Metadata.Tools.Components {
+			tools = append(tools, tool.Name)
+		}
+
+		assert.Equal(t, []string{"cdxgen", "xbom"}, tools)
+	})
+
+	t.Run("detected packages are linked", func(t *testing.T) {
+		components := testBOMComponents(bom)
+		assert.Len(t, components, 4, "only the crypto asset is added")
+		assert.Contains(t, components, aes.Id)
+
+		openaiComponent := components["pkg:pypi/openai@1.0.0"]
+		assert.Len(t, *openaiComponent.Evidence.Occurrences, 2)
+		assert.ElementsMatch(t, []cdx.Property{
+			{Name: "signature", Value: openai.Id},
+			{Name: "signature", Value: openaiAsync.Id},
+			{Name: "ai", Value: "true"},
+			{Name: "saas", Value: "true"},
+		}, *openaiComponent.Properties)
+
+		assert.Len(t, *components["pubsub-js"].Evidence.Occurrences, 3, "linked to the package with the most evidences")
+		assert.Nil(t, components["pubsub-py"].Evidence)
+	})
+
+	t.Run("services and dependencies refer to linked packages", func(t *testing.T) {
+		require.Len(t, *bom.Services, 2)
+		for _, service := range *bom.Services {
+			if service.BOMRef == "service:openai.client" {
+				assert.Contains(t, *service.Properties, cdx.Property{Name: "evidence", Value: "pkg:pypi/openai@1.0.0"})
+			}
+		}
+
+		dependencies := map[string][]string{}
+		for _, dependency := range *bom.Dependencies {
+			require.NotContains(t, dependencies, dependency.Ref, "dependency refs must be unique")
+			if dependency.Dependencies != nil {
+				dependencies[dependency.Ref] = *dependency.Dependencies
+			}
+		}
+
+		assert.Equal(t, []string{"golang.crypto.aes", "pkg:pypi/openai@1.0.0", "pubsub-js"},
+			dependencies["pkg:npm/shop@1.0.0"])
+		assert.Equal(t, []string{"service:openai.client"}, dependencies["pkg:pypi/openai@1.0.0"])
+		assert.Equal(t, []string{"service:gcp.pubsub"}, dependencies["pubsub-js"])
+	})
+}
+
+// testBOMRefs returns the bom-refs of the components, including nested components,
+// services and annotations of the BOM
+func testBOMRefs(bom *cdx.BOM) []string {
+	refs := []string{bom.Metadata.Component.BOMRef}
+	walkComponents(bom.Metadata.Component.Components, func(component *cdx.Component) {
+		refs = append(refs, component.BOMRef)
+	})
+	walkComponents(bom.Components, func(component *cdx.Component) {
+		refs = append(refs, component.BOMRef)
+	})
+
+	for _, service := range *bom.Services {
+		refs = append(refs, service.BOMRef)
+	}
+
+	for _, annotation := range *bom.Annotations {
+		refs = append(refs, annotation.BOMRef)
+	}
+
+	return refs
+}
+
+func TestCycloneDXReporter_ReEnrichBOM(t *testing.T) {
+	inputBom := cdx.NewBOM()
+	inputBom.SerialNumber = "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"
+	inputBom.Metadata = &cdx.Metadata{
+		Component: &cdx.Component{BOMRef: "pkg:pypi/shop@1.0.0", Type: cdx.ComponentTypeApplication, Name: "shop"},
+	}
+	inputBom.Components = utils.PtrTo([]cdx.Component{
+		{
+			BOMRef: "pkg:pypi/shop-api@1.0.0", PackageURL: "pkg:pypi/shop-api@1.0.0",
+			Type: cdx.ComponentTypeLibrary, Name: "shop-api",
+			Components: utils.PtrTo([]cdx.Component{
+				{BOMRef: "pkg:pypi/openai@1.0.0", PackageURL: "pkg:pypi/openai@1.0.0", Type: cdx.ComponentTypeLibrary, Name: "openai"},
+			}),
+		},
+	})
+
+	openai := &callgraphv1.Signature{Id: "openai.client", Vendor: "OpenAI", Tags: []string{"ai", "llm", "saas"}}
+	aes := &callgraphv1.Signature{Id: "golang.crypto.aes", Service: "AES", Tags: []string{"crypto"}}
+
+	config := CycloneDXReporterConfig{
+		ModelMetadata: map[string]common.ModelMetadata{
+			openai.Id: {Name: "GPT", Provider: "OpenAI"},
+		},
+	}
+
+	findings := func() *common.CodeAnalysisFindings {
+		openaiMatchResult := cdxTestMatchResult(openai, "openai.OpenAI")
+		openaiMatchResult.MatchedLanguageCode = core.LanguageCodePython
+		openaiMatchResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{{{CalleeNamespace: "openai//OpenAI"}}}
+
+		return &common.CodeAnalysisFindings{
+			SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+				openai.Id: {openaiMatchResult},
+				aes.Id:    {cdxTestMatchResult(aes, "crypto/aes.NewCipher")},
+			},
+		}
+	}
+
+	config.InputBOM = inputBom
+	enrichedBom := generateTestBOM(t, config, findings())
+
+	config.InputBOM = enrichedBom
+	reEnrichedBom := generateTestBOM(t, config, findings())
+
+	for _, bom := range []*cdx.BOM{enrichedBom, reEnrichedBom} {
+		refs := testBOMRefs(bom)
+		slices.Sort(refs)
+		assert.Equal(t, slices.Compact(slices.Clone(refs)), refs, "bom-refs must be unique")
+	}
+
+	assert.ElementsMatch(t, testBOMRefs(enrichedBom), testBOMRefs(reEnrichedBom))
+	assert.Len(t, *reEnrichedBom.Metadata.Tools.Components, 1)
+	assert.Equal(t, 3, reEnrichedBom.Version)
+
+	t.Run("nested packages are linked", func(t *testing.T) {
+		openaiComponent := findComponent(reEnrichedBom.Components, "pkg:pypi/openai@1.0.0")
+		require.NotNil(t, openaiComponent)
+		require.NotNil(t, openaiComponent.Evidence)
+		assert.Len(t, *openaiComponent.Evidence.Occurrences, 1, "evidences are not repeated")
+		assert.Len(t, *openaiComponent.Evidence.Identity, 1)
+		assert.NotContains(t, testBOMComponents(reEnrichedBom), openai.Id)
+	})
+
+	t.Run("models are replaced", func(t *testing.T) {
+		model := testBOMComponents(reEnrichedBom)["model:openai/gpt"]
+		require.NotNil(t, model.Properties)
+		assert.Contains(t, *model.Properties, cdx.Property{Name: "occurrences", Value: "1"})
+	})
+}
+
+func TestCycloneDXReporter_ReEnrichBOMRemovedFinding(t *testing.T) {
+	inputBom := cdx.NewBOM()
+	inputBom.SerialNumber = "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"
+	inputBom.Metadata = &cdx.Metadata{
+		Component: &cdx.Component{BOMRef: "pkg:pypi/shop@1.0.0", Type: cdx.ComponentTypeApplication, Name: "shop"},
+	}
+
+	// The package has an evidence of the tool which generated the BOM
+	inputOccurrence := cdx.EvidenceOccurrence{Location: "requirements.txt"}
+	inputBom.Components = utils.PtrTo([]cdx.Component{
+		{
+			BOMRef: "pkg:pypi/openai@1.0.0", PackageURL: "pkg:pypi/openai@1.0.0",
+			Type: cdx.ComponentTypeLibrary, Name: "openai",
+			Evidence: &cdx.Evidence{Occurrences: utils.PtrTo([]cdx.EvidenceOccurrence{inputOccurrence})},
+		},
+	})
+	inputBom.Dependencies = utils.PtrTo([]cdx.Dependency{
+		{Ref: "pkg:pypi/shop@1.0.0", Dependencies: utils.PtrTo([]string{"pkg:pypi/openai@1.0.0"})},
+	})
+
+	openai := &callgraphv1.Signature{Id: "openai.client", Vendor: "OpenAI", Tags: []string{"ai", "llm", "saas"}}
+	aes := &callgraphv1.Signature{Id: "golang.crypto.aes", Service: "AES", Tags: []string{"crypto"}}
+	md5 := &callgraphv1.Signature{Id: "golang.crypto.md5", Service: "MD5", Tags: []string{"crypto"}}
+
+	openaiMatchResult := cdxTestMatchResult(openai, "openai.OpenAI")
+	openaiMatchResult.MatchedLanguageCode = core.LanguageCodePython
+	openaiMatchResult.EvidenceMetadata = [][]callgraph.EvidenceMetadata{{{CalleeNamespace: "openai//OpenAI"}}}
+
+	config := CycloneDXReporterConfig{
+		ModelMetadata: map[string]common.ModelMetadata{
+			openai.Id: {Name: "GPT", Provider: "OpenAI"},
+		},
+		InputBOM: inputBom,
+	}
+
+	enrichedBom := generateTestBOM(t, config, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			openai.Id: {openaiMatchResult},
+			aes.Id:    {cdxTestMatchResult(aes, "crypto/aes.NewCipher")},
+			md5.Id:    {cdxTestMatchResult(md5, "crypto/md5.New")},
+		},
+	})
+
+	require.Contains(t, testBOMComponents(enrichedBom), "model:openai/gpt")
+
+	// The OpenAI client and MD5 are no longer used
+	config.InputBOM = enrichedBom
+	reEnrichedBom := generateTestBOM(t, config, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			aes.Id: {cdxTestMatchResult(aes, "crypto/aes.NewCipher")},
+		},
+	})
+
+	components := testBOMComponents(reEnrichedBom)
+	assert.Contains(t, components, aes.Id)
+	assert.NotContains(t, components, md5.Id)
+	assert.NotContains(t, components, "model:openai/gpt")
+	assert.Empty(t, *reEnrichedBom.Services)
+
+	t.Run("packages are kept without merged signatures", func(t *testing.T) {
+		openaiComponent := components["pkg:pypi/openai@1.0.0"]
+		assert.Nil(t, openaiComponent.Properties)
+		require.NotNil(t, openaiComponent.Evidence)
+		assert.Nil(t, openaiComponent.Evidence.Identity)
+		assert.Equal(t, []cdx.EvidenceOccurrence{inputOccurrence}, *openaiComponent.Evidence.Occurrences)
+	})
+
+	t.Run("dependencies are removed", func(t *testing.T) {
+		dependencies := map[string][]string{}
+		for _, dependency := range *reEnrichedBom.Dependencies {
+			dependencies[dependency.Ref] = nil
+			if dependency.Dependencies != nil {
+				dependencies[dependency.Ref] = *dependency.Dependencies
+			}
+		}
+
+		assert.NotContains(t, dependencies, md5.Id)
+		assert.NotContains(t, dependencies, "model:openai/gpt")
+		assert.Empty(t, dependencies["pkg:pypi/openai@1.0.0"])
+		assert.ElementsMatch(t, []string{"pkg:pypi/openai@1.0.0", aes.Id}, dependencies["pkg:pypi/shop@1.0.0"])
+	})
+}
+
+func TestCycloneDXReporter_MachineLearningModels(t *testing.T) {
+	anthropic := &callgraphv1.Signature{
+		Id:      "anthropic.client",
+		Vendor:  "Anthropic",
+		Product: "Anthropic API",
+		Service: "AI client",
+		Tags:    []string{"ai", "text", "llm", "saas"},
+	}
+	anthropicBedrock := &callgraphv1.Signature{
+		Id:      "anthropic.bedrock",
+		Vendor:  "Anthropic",
+		Product: "Anthropic Bedrock Integration",
+		Service: "Anthropic AWS Bedrock AI client",
+		Tags:    []string{"ai", "text", "llm", "bedrock"},
+	}
+	embeddings := &callgraphv1.Signature{
+		Id:      "openai.embeddings",
+		Vendor:  "OpenAI",
+		Product: "OpenAI",
+		Service: "Embeddings",
+		Tags:    []string{"ai", "embeddings", "text"},
+	}
+	vision := &callgraphv1.Signature{
+		Id:      "gcp.vision",
+		Vendor:  "Google",
+		Product: "Google Cloud Platform",
+		Service: "Vision API",
+		Tags:    []string{"vision", "image-analysis", "ai", "saas"},
+	}
+	crewaiLLMs := &callgraphv1.Signature{
+		Id:      "crewai.llms",
+		Vendor:  "CrewAI Inc.",
+		Product: "CrewAI",
+		Service: "LLMs",
+		Tags:    []string{"llms", "crewai"},
+	}
+	storage := &callgraphv1.Signature{
+		Id:   "langchain.storage",
+		Tags: []string{"ai", "storage", "langchain"},
+	}
+
+	bom := generateTestBOM(t, CycloneDXReporterConfig{
+		ModelMetadata: map[string]common.ModelMetadata{
+			anthropic.Id:        {Name: "Claude", Provider: "Anthropic"},
+			anthropicBedrock.Id: {Name: "Claude", Provider: "Anthropic"},
+		},
+	}, &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			anthropic.Id: {cdxTestMatchResult(anthropic, "anthropic.Anthropic")},
+			anthropicBedrock.Id: {cdxTestMatchResult(anthropicBedrock,
+				"anthropic.AnthropicBedrock", "anthropic.AsyncAnthropicBedrock")},
+			embeddings.Id: {cdxTestMatchResult(embeddings, "openai.embeddings.create")},
+			vision.Id:     {cdxTestMatchResult(vision, "google.cloud.vision.ImageAnnotatorClient")},
+			storage.Id:    {cdxTestMatchResult(storage, "langchain.storage.InMemoryStore")},
+		},
+		SuppressedMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			crewaiLLMs.Id: {cdxTestMatchResult(crewaiLLMs, "crewai.LLM")},
+		},
+	})
+
+	components := testBOMComponents(bom)
+
+	models := map[string]cdx.Component{}
+	for _, component := range components {
+		if component.Type == cdx.ComponentTypeMachineLearningModel {
+			models[component.BOMRef] = component
+		}
+	}
+
+	assert.Len(t, models, 4, "frameworks without a model task have no model, SDKs of a model share it")
+
+	tests := []struct {
+		bomRef   string
+		name     string
+		task     string
+		provider string
+	}{
+		{"model:anthropic/claude", "Claude", "text-generation", "Anthropic"},
+		{"model:openai.embeddings", "OpenAI - Embeddings model", "feature-extraction", ""},
+		{"model:gcp.vision", "Google Cloud Platform - Vision API model", "image-classification", "Google"},
+		{"model:crewai.llms", "CrewAI - LLMs model", "text-generation", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.bomRef, func(t *testing.T) {
+			model, ok := models[test.bomRef]
+			require.True(t, ok)
+
+			assert.Equal(t, test.name, model.Name)
+			require.NotNil(t, model.ModelCard)
+			require.NotNil(t, model.ModelCard.ModelParameters)
+			assert.Equal(t, test.task, model.ModelCard.ModelParameters.Task)
+
+			if test.provider == "" {
+				assert.Nil(t, model.Supplier)
+			} else {
+				require.NotNil(t, model.Supplier)
+				assert.Equal(t, test.provider, model.Supplier.Name)
+			}
+		})
+	}
+
+	t.Run("sdk components are kept", func(t *testing.T) {
+		assert.Equal(t, cdx.ComponentTypeLibrary, components[anthropic.Id].Type)
+		assert.Contains(t, *models["model:anthropic/claude"].Properties,
+			cdx.Property{Name: "evidence", Value: anthropic.Id})
+		assert.Contains(t, *models["model:crewai.llms"].Properties,
+			cdx.Property{Name: "suppressed", Value: "true"})
+
+		assert.Contains(t, *bom.Dependencies, cdx.Dependency{
+			Ref:          anthropic.Id,
+			Dependencies: utils.PtrTo([]string{"model:anthropic/claude", "service:anthropic.client"}),
+		})
+		assert.Contains(t, *bom.Dependencies, cdx.Dependency{Ref: "model:anthropic/claude"})
+	})
+
+	t.Run("sdks of a model are merged", func(t *testing.T) {
+		properties := *models["model:anthropic/claude"].Properties
+		assert.Contains(t, properties, cdx.Property{Name: "evidence", Value: anthropic.Id})
+		assert.Contains(t, properties, cdx.Property{Name: "evidence", Value: anthropicBedrock.Id})
+		assert.Contains(t, properties, cdx.Property{Name: "occurrences", Value: "3"})
+		assert.Contains(t, properties, cdx.Property{Name: "saas", Value: "true"})
+
+		assert.Contains(t, *bom.Dependencies, cdx.Dependency{
+			Ref:          anthropicBedrock.Id,
+			Dependencies: utils.PtrTo([]string{"model:anthropic/claude"}),
+		})
+	})
+}
+
+func TestCycloneDXReporter_FrameworkModels(t *testing.T) {
+	allSignatures, metadata, err := signatures.LoadAllSignaturesWithMetadata(nil)
+	require.NoError(t, err)
+
+	frameworkSignatures := map[string]*callgraphv1.Signature{}
+	for _, signature := range allSignatures {
+		frameworkSignatures[signature.GetId()] = signature
+	}
+
+	tests := []struct {
+		signatureId string
+		model       bool
+	}{
+		{"langchain.agents", false},
+		{"langchain_core.agents", false},
+		{"langchain_community.agents", false},
+		{"langchain_community.chat_loaders", false},
+		{"langchain_community.document_loaders", false},
+		{"crewai.agent", false},
+		{"crewai.agent-module", false},
+		{"langchain_community.llms", true},
+		{"crewai.llms", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.signatureId, func(t *testing.T) {
+			signature, ok := frameworkSignatures[test.signatureId]
+			require.True(t, ok)
+			assert.NotContains(t, metadata.Models, test.signatureId, "frameworks do not name a model")
+
+			bom := generateTestBOM(t, CycloneDXReporterConfig{ModelMetadata: metadata.Models},
+				&common.CodeAnalysisFindings{
+					SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+						signature.Id: {cdxTestMatchResult(signature, "framework.Call")},
+					},
+				})
+
+			var models []cdx.Component
+			for _, component := range testBOMComponents(bom) {
+				if component.Type == cdx.ComponentTypeMachineLearningModel {
+					models = append(models, component)
+				}
+			}
+
+			if !test.model {
+				assert.Empty(t, models)
+				return
+			}
+
+			require.Len(t, models, 1)
+			assert.Equal(t, "model:"+test.signatureId, models[0].BOMRef, "models of frameworks have no name")
+			assert.Nil(t, models[0].Supplier)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package reporter
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// projectManifests identify the root directory of a project
+var projectManifests = []string{"go.mod", "package.json", "pyproject.toml"}
+
+// rootProject is the project of files not within any sub-application
+const rootProject = ""
+
+// applicationBomRef is the bom-ref of the sub-application for a project directory
+func applicationBomRef(project string) string {
+	return "application:" + project
+}
+
+// recordComponentProjects records the projects of the files using a component
+func (c *CycloneDXReporter) recordComponentProjects(componentBomRef string,
+	matchResults []common.EnrichedSignatureMatchResult) {
+	projects, ok := c.componentProjects[componentBomRef]
+	if !ok {
+		projects = map[string]bool{}
+		c.componentProjects[componentBomRef] = projects
+	}
+
+	for _, matchResult := range matchResults {
+		projects[c.projectOf(matchResult.FilePath)] = true
+	}
+}
+
+// projectOf returns the slash separated path, relative to the source path, of the
+// closest directory with a project manifest containing the file. Files of the project
+// at the source path, or not within any project, belong to the root project.
+func (c *CycloneDXReporter) projectOf(filePath string) string {
+	dir, ok := common.RelativeSourcePath(c.config.SourcePath, filepath.Dir(filePath))
+	if !ok {
+		return rootProject
+	}
+
+	for ; dir != "."; dir = path.Dir(dir) {
+		if c.isProjectDir(dir) {
+			return dir
+		}
+	}
+
+	return rootProject
+}
+
+func (c *CycloneDXReporter) isProjectDir(dir string) bool {
+	if isProject, ok := c.projectDirs[dir]; ok {
+		return isProject
+	}
+
+	isProject := slices.ContainsFunc(projectManifests, func(manifest string) bool {
+		st, err := os.Stat(filepath.Join(c.config.SourcePath, filepath.FromSlash(dir), manifest))
+		return err == nil && !st.IsDir()
+	})
+
+	c.projectDirs[dir] = isProject
+	return isProject
+}
+
+// buildDependencyGraph attaches the components to the root component, through the
+// sub-application of their project when they are used within one. A component used
+// in multiple projects is a dependency of each of them.
+func (c *CycloneDXReporter) buildDependencyGraph() {
+	dependencies := map[string][]string{}
+	for _, componentBomRef := range c.detectedComponents {
+		for project := range c.componentProjects[componentBomRef] {
+			dependent := c.rootComponentBomref
+			if project != rootProject {
+				dependent = applicationBomRef(project)
+			}
+
+			dependencies[dependent] = append(dependencies[dependent], componentBomRef)
+		}
+	}
+
+	projects := []string{}
+	for _, componentProjects := range c.componentProjects {
+		for project := range componentProjects {
+			if project != rootProject && !slices.Contains(projects, project) {
+				projects = append(projects, project)
+			}
+		}
+	}
+
+	slices.Sort(projects)
+
+	applications := []cdx.Component{}
+	for _, project := range projects {
+		applications = append(applications, cdx.Component{
+			BOMRef: applicationBomRef(project),
+			Type:   cdx.ComponentTypeApplication,
+			Name:   project,
+		})
+
+		dependencies[c.rootComponentBomref] = append(dependencies[c.rootComponentBomref],
+			applicationBomRef(project))
+	}
+
+	// Sub-applications of an earlier enrichment of the BOM are replaced
+	for _, application := range applications {
+		upsertComponent(c.bom.Metadata.Component.Components, application)
+	}
+
+	// Every detected component is in the graph, including those without dependencies
+	refs := []string{c.rootComponentBomref}
+	for _, application := range applications {
+		refs = append(refs, application.BOMRef)
+	}
+
+	refs = append(refs, c.detectedComponents...)
+
+	for _, ref := range refs {
+		dependsOn := slices.Concat(dependencies[ref], c.dependsOn[ref])
+
+		// Dependencies of a BOM being enriched are extended, refs must be unique in the graph
+		index := slices.IndexFunc(*c.bom.Dependencies, func(dependency cdx.Dependency) bool {
+			return dependency.Ref == ref
+		})
+
+		if index < 0 {
+			*c.bom.Dependencies = append(*c.bom.Dependencies, cdx.Dependency{Ref: ref})
+			index = len(*c.bom.Dependencies) - 1
+		}
+
+		dependency := &(*c.bom.Dependencies)[index]
+		if dependency.Dependencies != nil {
+			dependsOn = append(dependsOn, *dependency.Dependencies...)
+		}
+
+		if len(dependsOn) > 0 {
+			slices.Sort(dependsOn)
+			dependency.Dependencies = utils.PtrTo(slices.Compact(dependsOn))
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package reporter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	packageurl "github.com/package-url/packageurl-go"
+	"github.com/safedep/code/core"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// purlLanguages are the languages of code using packages of a package URL type
+var purlLanguages = map[string]core.LanguageCode{
+	packageurl.TypeGolang: core.LanguageCodeGo,
+	packageurl.TypeNPM:    core.LanguageCodeJavascript,
+	packageurl.TypePyPi:   core.LanguageCodePython,
+}
+
+// dependencyComponent is a component of the input BOM identifying a package
+// which may be used by the detected signatures
+type dependencyComponent struct {
+	bomRef   string
+	language core.LanguageCode
+
+	// importPaths are the slash separated paths used to import the package
+	importPaths []string
+}
+
+// prepareInputBom keeps the components and metadata of the input BOM, adding the
+// tool to its metadata. The content of an earlier enrichment by the tool is removed.
+func prepareInputBom(bom *cdx.BOM, config CycloneDXReporterConfig, toolComponent cdx.Component) {
+	bom.SpecVersion = max(bom.SpecVersion, cdx.SpecVersion1_6)
+
+	if bom.Metadata == nil {
+		bom.Metadata = &cdx.Metadata{}
+	}
+
+	if bom.Metadata.Component == nil {
+		bom.Metadata.Component = &cdx.Component{
+			Type: cdx.ComponentTypeApplication,
+			Name: config.ApplicationComponentName,
+		}
+	}
+
+	if bom.Metadata.Component.BOMRef == "" {
+		bom.Metadata.Component.BOMRef = "root-application"
+	}
+
+	if bom.Metadata.Component.Components == nil {
+		bom.Metadata.Component.Components = utils.PtrTo([]cdx.Component{})
+	}
+
+	// Tools are listed either as components or in the deprecated format, but not both.
+	// The tool replaces itself when the BOM was already enriched by any version of it.
+	toolBomRefs := []string{toolComponent.BOMRef}
+
+	switch tools := bom.Metadata.Tools; {
+	case tools == nil:
+		bom.Metadata.Tools = &cdx.ToolsChoice{Components: utils.PtrTo([]cdx.Component{toolComponent})}
+	case tools.Tools != nil:
+		*tools.Tools = slices.DeleteFunc(*tools.Tools, func(tool cdx.Tool) bool {
+			return tool.Vendor == toolComponent.Group && tool.Name == toolComponent.Name
+		})
+
+		*tools.Tools = append(*tools.Tools, cdx.Tool{
+			Vendor:  toolComponent.Group,
+			Name:    toolComponent.Name,
+			Version: toolComponent.Version,
+		})
+	case tools.Components != nil:
+		*tools.Components = slices.DeleteFunc(*tools.Components, func(tool cdx.Component) bool {
+			replaced := (tool.BOMRef != "" && tool.BOMRef == toolComponent.BOMRef) ||
+				(tool.Group == toolComponent.Group && tool.Name == toolComponent.Name)
+			if replaced {
+				toolBomRefs = append(toolBomRefs, tool.BOMRef)
+			}
+
+			return replaced
+		})
+
+		*tools.Components = append(*tools.Components, toolComponent)
+	default:
+		tools.Components = utils.PtrTo([]cdx.Component{toolComponent})
+	}
+
+	if bom.Components == nil {
+		bom.Components = utils.PtrTo([]cdx.Component{})
+	}
+
+	if bom.Services == nil {
+		bom.Services = utils.PtrTo([]cdx.Service{})
+	}
+
+	if bom.Dependencies == nil {
+		bom.Dependencies = utils.PtrTo([]cdx.Dependency{})
+	}
+
+	if bom.Vulnerabilities == nil {
+		bom.Vulnerabilities = utils.PtrTo([]cdx.Vulnerability{})
+	}
+
+	removeEnrichment(bom, toolBomRefs)
+}
+
+// removeEnrichment removes the content added by an earlier enrichment of the BOM by
+// the tool with one of the bom-refs, like the annotations of the tool are replaced, so
+// that findings no longer detected are not retained. Components detected by the tool,
+// their models, services and sub-applications are removed along with their dependencies.
+// Packages of the BOM are kept without the signatures and evidences merged into them.
+func removeEnrichment(bom *cdx.BOM, toolBomRefs []string) {
+	walkComponents(bom.Components, func(component *cdx.Component) {
+		if hasProperty(component.Properties, "signature") {
+			removeMergedSignatures(component, toolBomRefs)
+		}
+	})
+
+	removed := deleteComponents(bom.Components, func(component *cdx.Component) bool {
+		if strings.HasPrefix(component.BOMRef, "model:") &&
+			component.Type == cdx.ComponentTypeMachineLearningModel && hasProperty(component.Properties, "evidence") {
+			return true
+		}
+
+		return component.PackageURL == "" && component.Evidence != nil && component.Evidence.Identity != nil &&
+			slices.ContainsFunc(*component.Evidence.Identity, func(identity cdx.EvidenceIdentity) bool {
+				return isToolIdentity(identity, toolBomRefs)
+			})
+	})
+
+	removed = append(removed, deleteComponents(bom.Metadata.Component.Components, func(component *cdx.Component) bool {
+		return strings.HasPrefix(component.BOMRef, "application:")
+	})...)
+
+	*bom.Services = slices.DeleteFunc(*bom.Services, func(service cdx.Service) bool {
+		detected := strings.HasPrefix(service.BOMRef, "service:") && hasProperty(service.Properties, "evidence")
+		if detected {
+			removed = append(removed, service.BOMRef)
+		}
+
+		return detected
+	})
+
+	*bom.Dependencies = slices.DeleteFunc(*bom.Dependencies, func(dependency cdx.Dependency) bool {
+		return slices.Contains(removed, dependency.Ref)
+	})
+
+	for i := range *bom.Dependencies {
+		dependency := &(*bom.Dependencies)[i]
+		if dependency.Dependencies == nil {
+			continue
+		}
+
+		*dependency.Dependencies = slices.DeleteFunc(*dependency.Dependencies, func(ref string) bool {
+			return slices.Contains(removed, ref)
+		})
+
+		if len(*dependency.Dependencies) == 0 {
+			dependency.Dependencies = nil
+		}
+	}
+}
+
+// deleteComponents deletes the components for which the function returns true from
+// the tree of components, returning the bom-refs of the deleted components along with
+// those of their nested components
+func deleteComponents(components *[]cdx.Component, fn func(component *cdx.Component) bool) []string {
+	if components == nil {
+		return nil
+	}
+
+	deleted := []string{}
+	*components = slices.DeleteFunc(*components, func(component cdx.Component) bool {
+		if !fn(&component) {
+			return false
+		}
+
+		deleted = append(deleted, component.BOMRef)
+		walkComponents(component.Components, func(nested *cdx.Component) {
+			deleted = append(deleted, nested.BOMRef)
+		})
+
+		return true
+	})
+
+	for i := range *components {
+		deleted = append(deleted, deleteComponents((*components)[i].Components, fn)...)
+	}
+
+	return deleted
+}
+
+// removeMergedSignatures removes the signatures merged into a package of the BOM,
+// along with their known tags and evidences
+func removeMergedSignatures(component *cdx.Component, toolBomRefs []string) {
+	signatureIds := []string{}
+	*component.Properties = slices.DeleteFunc(*component.Properties, func(property cdx.Property) bool {
+		if property.Name == "signature" {
+			signatureIds = append(signatureIds, property.Value)
+			return true
+		}
+
+		return slices.Contains(cdxKnownTags, property.Name) && property.Value == "true"
+	})
+
+	if len(*component.Properties) == 0 {
+		component.Properties = nil
+	}
+
+	evidence := component.Evidence
+	if evidence == nil {
+		return
+	}
+
+	if evidence.Identity != nil {
+		*evidence.Identity = slices.DeleteFunc(*evidence.Identity, func(identity cdx.EvidenceIdentity) bool {
+			return isToolIdentity(identity, toolBomRefs)
+		})
+
+		if len(*evidence.Identity) == 0 {
+			evidence.Identity = nil
+		}
+	}
+
+	if evidence.Occurrences != nil {
+		*evidence.Occurrences = slices.DeleteFunc(*evidence.Occurrences, func(occurrence cdx.EvidenceOccurrence) bool {
+			return slices.ContainsFunc(signatureIds, func(signatureId string) bool {
+				return strings.HasPrefix(occurrence.BOMRef, occurrenceBomRefPrefix(signatureId))
+			})
+		})
+
+		if len(*evidence.Occurrences) == 0 {
+			evidence.Occurrences = nil
+		}
+	}
+
+	if *evidence == (cdx.Evidence{}) {
+		component.Evidence = nil
+	}
+}
+
+// isToolIdentity returns true for an identity evidence of the tool with one of the bom-refs
+func isToolIdentity(identity cdx.EvidenceIdentity, toolBomRefs []string) bool {
+	return identity.Tools != nil && slices.ContainsFunc(*identity.Tools, func(tool cdx.BOMReference) bool {
+		return slices.Contains(toolBomRefs, string(tool))
+	})
+}
+
+func hasProperty(properties *[]cdx.Property, name string) bool {
+	return properties != nil && slices.ContainsFunc(*properties, func(property cdx.Property) bool {
+		return property.Name == name
+	})
+}
+
+// occurrenceBomRefPrefix prefixes the bom-refs of the occurrences of a signature merged
+// into a package, identifying them when the BOM is enriched again
+func occurrenceBomRefPrefix(signatureId string) string {
+	return "occurrence:" + signatureId + ":"
+}
+
+// walkComponents calls the function with every component of the tree of components,
+// parents before their nested components
+func walkComponents(components *[]cdx.Component, fn func(component *cdx.Component)) {
+	if components == nil {
+		return
+	}
+
+	for i := range *components {
+		fn(&(*components)[i])
+		walkComponents((*components)[i].Components, fn)
+	}
+}
+
+// findComponent returns the component with the bom-ref in the tree of components
+func findComponent(components *[]cdx.Component, bomRef string) *cdx.Component {
+	var found *cdx.Component
+	walkComponents(components, func(component *cdx.Component) {
+		if found == nil && component.BOMRef == bomRef {
+			found = component
+		}
+	})
+
+	return found
+}
+
+// upsertComponent adds the component, replacing the component with the same bom-ref
+// anywhere in the tree of components such as one detected when the BOM was enriched
+// before, so that bom-refs remain unique
+func upsertComponent(components *[]cdx.Component, component cdx.Component) {
+	if existing := findComponent(components, component.BOMRef); existing != nil {
+		*existing = component
+		return
+	}
+
+	*components = append(*components, component)
+}
+
+// upsertService adds the service, replacing the service with the same bom-ref
+func upsertService(services *[]cdx.Service, service cdx.Service) {
+	index := slices.IndexFunc(*services, func(s cdx.Service) bool {
+		return s.BOMRef == service.BOMRef
+	})
+
+	if index >= 0 {
+		(*services)[index] = service
+		return
+	}
+
+	*services = append(*services, service)
+}
+
+// dependencyComponents returns the components of the BOM, including nested components,
+// identifying packages of the supported languages by their package URL
+func dependencyComponents(bom *cdx.BOM) []dependencyComponent {
+	components := []dependencyComponent{}
+	walkComponents(bom.Components, func(component *cdx.Component) {
+		if component.BOMRef == "" || component.PackageURL == "" {
+			return
+		}
+
+		purl, err := packageurl.FromString(component.PackageURL)
+		if err != nil {
+			return
+		}
+
+		language, ok := purlLanguages[purl.Type]
+		if !ok {
+			return
+		}
+
+		name := purl.Name
+		if purl.Namespace != "" {
+			name = purl.Namespace + "/" + purl.Name
+		}
+
+		importPaths := []string{name}
+
+		// Python packages are imported by module names which commonly differ from
+		// the distribution name by the separator, eg. google-cloud-pubsub
+		if purl.Type == packageurl.TypePyPi {
+			name = strings.ToLower(name)
+			importPaths = []string{
+				strings.NewReplacer("-", "/", "_", "/", ".", "/").Replace(name),
+				strings.NewReplacer("-", "_", ".", "/").Replace(name),
+			}
+		}
+
+		components = append(components, dependencyComponent{
+			bomRef:      component.BOMRef,
+			language:    language,
+			importPaths: slices.Compact(importPaths),
+		})
+	})
+
+	return components
+}
+
+// linkedDependency returns the bom-ref of the dependency component imported by the most
+// evidences of the match results. The callee namespaces of evidences are matched with
+// the import paths of the packages.
+func (c *CycloneDXReporter) linkedDependency(matchResults []common.EnrichedSignatureMatchResult) (string, bool) {
+	evidences := map[string]int{}
+	for _, matchResult := range matchResults {
+		for i, condition := range matchResult.MatchedConditions {
+			for j := range condition.Evidences {
+				calleePath := strings.ReplaceAll(matchResult.Metadata(i, j).CalleeNamespace, "//", "/")
+				if bomRef, ok := c.importedDependency(matchResult.MatchedLanguageCode, calleePath); ok {
+					evidences[bomRef]++
+				}
+			}
+		}
+	}
+
+	linkedBomRef := ""
+	for bomRef, count := range evidences {
+		if count > evidences[linkedBomRef] || (count == evidences[linkedBomRef] && bomRef < linkedBomRef) {
+			linkedBomRef = bomRef
+		}
+	}
+
+	return linkedBomRef, linkedBomRef != ""
+}
+
+// importedDependency returns the bom-ref of the dependency component with the longest
+// import path containing the callee
+func (c *CycloneDXReporter) importedDependency(language core.LanguageCode, calleePath string) (string, bool) {
+	bomRef, longestImportPath := "", ""
+	for _, dependency := range c.dependencies {
+		if dependency.language != language {
+			continue
+		}
+
+		for _, importPath := range dependency.importPaths {
+			imported := calleePath == importPath || strings.HasPrefix(calleePath, importPath+"/")
+			if imported && len(importPath) > len(longestImportPath) {
+				bomRef, longestImportPath = dependency.bomRef, importPath
+			}
+		}
+	}
+
+	return bomRef, bomRef != ""
+}
+
+// mergeIntoComponent adds the evidences of a detected component to the dependency
+// component with the bom-ref, along with the signature and its known tags. Evidences
+// already recorded, such as when the BOM was enriched before, are not repeated.
+func (c *CycloneDXReporter) mergeIntoComponent(bomRef string, detected cdx.Component, signature *callgraphv1.Signature) {
+	component := findComponent(c.bom.Components, bomRef)
+	if component.Evidence == nil {
+		component.Evidence = &cdx.Evidence{}
+	}
+
+	if component.Evidence.Identity == nil {
+		component.Evidence.Identity = utils.PtrTo([]cdx.EvidenceIdentity{})
+	}
+
+	if component.Evidence.Occurrences == nil {
+		component.Evidence.Occurrences = utils.PtrTo([]cdx.EvidenceOccurrence{})
+	}
+
+	for _, identity := range *detected.Evidence.Identity {
+		if !slices.ContainsFunc(*component.Evidence.Identity, func(existing cdx.EvidenceIdentity) bool {
+			return evidenceIdentityKey(existing) == evidenceIdentityKey(identity)
+		}) {
+			*component.Evidence.Identity = append(*component.Evidence.Identity, identity)
+		}
+	}
+
+	for i, occurrence := range *detected.Evidence.Occurrences {
+		occurrence.BOMRef = occurrenceBomRefPrefix(signature.GetId()) + strconv.Itoa(i)
+		if !slices.ContainsFunc(*component.Evidence.Occurrences, func(existing cdx.EvidenceOccurrence) bool {
+			return evidenceOccurrenceKey(existing) == evidenceOccurrenceKey(occurrence)
+		}) {
+			*component.Evidence.Occurrences = append(*component.Evidence.Occurrences, occurrence)
+		}
+	}
+
+	if component.Properties == nil {
+		component.Properties = utils.PtrTo([]cdx.Property{})
+	}
+
+	// Multiple signatures may be detected for the same package
+	properties := append([]cdx.Property{{Name: "signature", Value: signature.GetId()}},
+		c.getKnownTaggedProperties(signature.GetTags())...)
+	for _, property := range properties {
+		if !slices.Contains(*component.Properties, property) {
+			*component.Properties = append(*component.Properties, property)
+		}
+	}
+}
+
+// evidenceIdentityKey identifies an identity evidence by its field, methods and tools
+func evidenceIdentityKey(identity cdx.EvidenceIdentity) string {
+	key := string(identity.Field)
+	if identity.Methods != nil {
+		for _, method := range *identity.Methods {
+			key += "|" + string(method.Technique)
+		}
+	}
+
+	if identity.Tools != nil {
+		for _, tool := range *identity.Tools {
+			key += "|" + string(tool)
+		}
+	}
+
+	return key
+}
+
+// evidenceOccurrenceKey identifies an occurrence evidence by its location and context
+func evidenceOccurrenceKey(occurrence cdx.EvidenceOccurrence) string {
+	line, offset := 0, 0
+	if occurrence.Line != nil {
+		line = *occurrence.Line
+	}
+
+	if occurrence.Offset != nil {
+		offset = *occurrence.Offset
+	}
+
+	return fmt.Sprintf("%s|%d|%d|%s", occurrence.Location, line, offset, occurrence.AdditionalContext)
+}
@@ -17,6 +17,13 @@ type HTMLReporterConfig struct {
 	SnippetAfterLines   int    // Number of context lines to show after match (default: 3)
 	SnippetMaxBytes     int    // Max total bytes for snippet (default: 5120 = 5KB)
 	SnippetMaxLineChars int    // Max characters per line (default: 500)
+
+	// NoSnippets omits code snippets, findings are reported with their location only
+	NoSnippets bool
+
+	// NoDefaults uses the config as is, so that zero context lines and no size
+	// limit can be set. Defaults replace zero values otherwise.
+	NoDefaults bool
 }
 
 type HTMLReporter struct {
@@ -26,19 +33,40 @@ type HTMLReporter struct {
 
 var _ Reporter = (*HTMLReporter)(nil)
 
-func NewHTMLReporter(config HTMLReporterConfig) (*HTMLReporter, error) {
-	// Set defaults for snippet configuration
-	if config.SnippetBeforeLines == 0 {
-		config.SnippetBeforeLines = 3
-	}
-	if config.SnippetAfterLines == 0 {
-		config.SnippetAfterLines = 3
+// DefaultHTMLReporterConfig returns the config with snippets of 3 context lines
+// limited to 5KB and 500 characters per line. The config is also applied by
+// NewHTMLReporter to zero values unless NoDefaults is set.
+func DefaultHTMLReporterConfig() HTMLReporterConfig {
+	return HTMLReporterConfig{
+		SnippetBeforeLines:  3,
+		SnippetAfterLines:   3,
+		SnippetMaxBytes:     5120, // 5KB
+		SnippetMaxLineChars: 500,
 	}
-	if config.SnippetMaxBytes == 0 {
-		config.SnippetMaxBytes = 5120 // 5KB
+}
+
+func NewHTMLReporter(config HTMLReporterConfig) (*HTMLReporter, error) {
+	err := validateSnippetOptions(config.SnippetBeforeLines, config.SnippetAfterLines,
+		config.SnippetMaxBytes, config.SnippetMaxLineChars)
+	if err != nil {
+		return nil, err
 	}
-	if config.SnippetMaxLineChars == 0 {
-		config.SnippetMaxLineChars = 500
+
+	if !config.NoDefaults {
+		defaults := DefaultHTMLReporterConfig()
+
+		if config.SnippetBeforeLines == 0 {
+			config.SnippetBeforeLines = defaults.SnippetBeforeLines
+		}
+		if config.SnippetAfterLines == 0 {
+			config.SnippetAfterLines = defaults.SnippetAfterLines
+		}
+		if config.SnippetMaxBytes == 0 {
+			config.SnippetMaxBytes = defaults.SnippetMaxBytes
+		}
+		if config.SnippetMaxLineChars == 0 {
+			config.SnippetMaxLineChars = defaults.SnippetMaxLineChars
+		}
 	}
 
 	return &HTMLReporter{
@@ -63,9 +91,9 @@ func (r *HTMLReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *common.C
 
 			fileMap := make(map[string]map[string]interface{})
 
-			for _, condition := range signatureMatchResult.MatchedConditions {
-				for _, evidence := range condition.Evidences {
-					evidenceMetadata := evidence.Metadata(signatureMatchResult.TreeData)
+			for i, condition := range signatureMatchResult.MatchedConditions {
+				for j := range condition.Evidences {
+					evidenceMetadata := signatureMatchResult.Metadata(i, j)
 
 					key := signatureMatchResult.FilePath + "|" + string(signatureMatchResult.MatchedLanguageCode)
 					if _, ok := fileMap[key]; !ok {
@@ -81,11 +109,16 @@ func (r *HTMLReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *common.C
 					// Create a match object with occurrence and snippet
 					match := map[string]interface{}{
 						"Occurrence": conditionValueString,
+						"Line":       0,
 						"Snippet":    nil,
 					}
 
-					// Add snippet if available
 					if evidenceMetadata.CallerIdentifierMetadata != nil {
+						match["Line"] = int(evidenceMetadata.CallerIdentifierMetadata.StartLine) + 1
+					}
+
+					// Add snippet if available
+					if evidenceMetadata.CallerIdentifierMetadata != nil && !r.config.NoSnippets {
 						startLine := int(evidenceMetadata.CallerIdentifierMetadata.StartLine)
 						endLine := int(evidenceMetadata.CallerIdentifierMetadata.EndLine)
 
@@ -177,6 +210,14 @@ func (r *HTMLReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *common.C
 		r.visualiser.AddRow(row)
 	}
 
+	for _, skippedFile := range codeAnalysisFindings.SkippedFiles {
+		r.visualiser.AddNotAnalyzed(skippedFile.Path, skipReasonDescription(skippedFile.Reason))
+	}
+
+	for _, failedFile := range codeAnalysisFindings.FailedFiles {
+		r.visualiser.AddDiagnostic(failedFile.Path, failedFile.Err.Error())
+	}
+
 	return nil
 }
 
@@ -208,14 +249,18 @@ func getHTMLTemplate() (string, error) {
 
 // HTMLVisualiser builds and writes an interactive HTML report
 type HTMLVisualiser struct {
-	headers []string
-	rows    []map[string]interface{}
+	headers     []string
+	rows        []map[string]interface{}
+	notAnalyzed []map[string]interface{}
+	diagnostics []map[string]interface{}
 }
 
 func NewHTMLVisualiser(headers []string) *HTMLVisualiser {
 	return &HTMLVisualiser{
-		headers: headers,
-		rows:    []map[string]interface{}{},
+		headers:     headers,
+		rows:        []map[string]interface{}{},
+		notAnalyzed: []map[string]interface{}{},
+		diagnostics: []map[string]interface{}{},
 	}
 }
 
@@ -223,6 +268,22 @@ func (hv *HTMLVisualiser) AddRow(row map[string]interface{}) {
 	hv.rows = append(hv.rows, row)
 }
 
+// AddNotAnalyzed lists a file skipped by the analysis along with the reason
+func (hv *HTMLVisualiser) AddNotAnalyzed(file, reason string) {
+	hv.notAnalyzed = append(hv.notAnalyzed, map[string]interface{}{
+		"File":   file,
+		"Reason": reason,
+	})
+}
+
+// AddDiagnostic lists a file whose analysis failed along with the error
+func (hv *HTMLVisualiser) AddDiagnostic(file, message string) {
+	hv.diagnostics = append(hv.diagnostics, map[string]interface{}{
+		"File":    file,
+		"Message": message,
+	})
+}
+
 func (hv *HTMLVisualiser) GenerateHtmlFile(htmlPath string) error {
 	// Map of popular languages to their CDN icon links
 	languageIconMap := map[string]string{
@@ -285,5 +346,7 @@ func (hv *HTMLVisualiser) GenerateHtmlFile(htmlPath string) error {
 		"Rows":            rows,
 		"UniqueTags":      uniqueTags,
 		"LanguageIconMap": languageIconMap,
+		"NotAnalyzed":     hv.notAnalyzed,
+		"Diagnostics":     hv.diagnostics,
 	})
 }
@@ -144,3 +144,20 @@ func TestTemplateWithEnhancedSnippetFields(t *testing.T) {
 	assert.NoError(t, err, "failed to parse HTML with enhanced snippet fields")
 	assert.NotNil(t, doc, "goquery document should not be nil")
 }
+
+func TestNewHTMLReporter_ConfigDefaults(t *testing.T) {
+	reporter, err := NewHTMLReporter(HTMLReporterConfig{HTMLReportPath: "test.html"})
+	assert.NoError(t, err)
+	assert.Equal(t, HTMLReporterConfig{
+		HTMLReportPath:      "test.html",
+		SnippetBeforeLines:  3,
+		SnippetAfterLines:   3,
+		SnippetMaxBytes:     5120,
+		SnippetMaxLineChars: 500,
+	}, reporter.config)
+
+	noDefaults := HTMLReporterConfig{HTMLReportPath: "test.html", NoDefaults: true}
+	reporter, err = NewHTMLReporter(noDefaults)
+	assert.NoError(t, err)
+	assert.Equal(t, noDefaults, reporter.config)
+}
@@ -0,0 +1,340 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// JSONReportSchemaVersion is the version of the JSON report schema. It follows
+// semantic versioning and must be updated on every change to the JSON report
+// types. The schema is documented in docs/json-report.md
+const JSONReportSchemaVersion = "1.4.0"
+
+type JSONReporterConfig struct {
+	Tool common.ToolMetadata
+
+	// Path defines the output file path
+	Path string
+
+	// SourcePath is the scanned directory. File paths are
+	// reported relative to this path when possible.
+	SourcePath string
+
+	// NoSnippets omits the caller content of findings
+	NoSnippets bool
+}
+
+// JSONReport is the document written by the JSON reporter
+type JSONReport struct {
+	SchemaVersion string        `json:"schema_version"`
+	GeneratedAt   string        `json:"generated_at"`
+	Tool          JSONTool      `json:"tool"`
+	SourcePath    string        `json:"source_path,omitempty"`
+	Findings      []JSONFinding `json:"findings"`
+
+	// NotAnalyzed are the files skipped for exceeding the analysis budgets
+	NotAnalyzed []JSONSkippedFile `json:"not_analyzed,omitempty"`
+
+	// Diagnostics are the files whose analysis failed in partial results mode
+	Diagnostics []JSONDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type JSONTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Purl    string `json:"purl,omitempty"`
+}
+
+// JSONFinding is a single evidence of a signature match
+type JSONFinding struct {
+	Signature       JSONSignature `json:"signature"`
+	Condition       JSONCondition `json:"condition"`
+	Language        string        `json:"language"`
+	FilePath        string        `json:"file_path"`
+	Range           *JSONRange    `json:"range,omitempty"`
+	CallerContent   string        `json:"caller_content,omitempty"`
+	CallerNamespace string        `json:"caller_namespace,omitempty"`
+	CalleeNamespace string        `json:"callee_namespace,omitempty"`
+
+	// Suppression is available only for suppressed findings
+	Suppression *JSONSuppression `json:"suppression,omitempty"`
+
+	// DiffStatus is available only for differential scans eg. "introduced"
+	DiffStatus string `json:"diff_status,omitempty"`
+}
+
+type JSONSkippedFile struct {
+	FilePath string `json:"file_path"`
+	Reason   string `json:"reason"`
+}
+
+type JSONDiagnostic struct {
+	FilePath string `json:"file_path"`
+	Error    string `json:"error"`
+}
+
+type JSONSuppression struct {
+	Source string `json:"source"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type JSONSignature struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Vendor      string   `json:"vendor,omitempty"`
+	Product     string   `json:"product,omitempty"`
+	Service     string   `json:"service,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+type JSONCondition struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// JSONRange is the source range of the matched code. Lines and
+// columns are 1-based, the end column is exclusive.
+type JSONRange struct {
+	Start JSONPosition `json:"start"`
+	End   JSONPosition `json:"end"`
+}
+
+type JSONPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type JSONReporter struct {
+	config      JSONReporterConfig
+	findings    []JSONFinding
+	notAnalyzed []JSONSkippedFile
+	diagnostics []JSONDiagnostic
+}
+
+var _ Reporter = (*JSONReporter)(nil)
+
+func NewJSONReporter(config JSONReporterConfig) (*JSONReporter, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("JSON report path is required")
+	}
+
+	return &JSONReporter{
+		config:   config,
+		findings: []JSONFinding{},
+	}, nil
+}
+
+func (r *JSONReporter) Name() string {
+	return "json"
+}
+
+func (r *JSONReporter) RecordCodeAnalysisFindings(findings *common.CodeAnalysisFindings) error {
+	r.recordMatchResults(findings.SignatureWiseMatchResults)
+	r.recordMatchResults(findings.SuppressedMatchResults)
+
+	for _, skippedFile := range findings.SkippedFiles {
+		r.notAnalyzed = append(r.notAnalyzed, JSONSkippedFile{
+			FilePath: reportFilePath(r.config.SourcePath, skippedFile.Path),
+			Reason:   string(skippedFile.Reason),
+		})
+	}
+
+	for _, failedFile := range findings.FailedFiles {
+		r.diagnostics = append(r.diagnostics, JSONDiagnostic{
+			FilePath: reportFilePath(r.config.SourcePath, failedFile.Path),
+			Error:    failedFile.Err.Error(),
+		})
+	}
+
+	return nil
+}
+
+// reportFilePath returns the path of a file relative to the scanned
+// source directory, falling back to the path as is
+func reportFilePath(sourcePath, path string) string {
+	if relPath, ok := common.RelativeSourcePath(sourcePath, path); ok {
+		return relPath
+	}
+
+	return filepath.ToSlash(path)
+}
+
+func (r *JSONReporter) recordMatchResults(matchResults map[string][]common.EnrichedSignatureMatchResult) {
+	for _, signatureMatchResults := range matchResults {
+		for i := range signatureMatchResults {
+			r.findings = append(r.findings,
+				jsonFindings(r.config.SourcePath, r.config.NoSnippets, &signatureMatchResults[i])...)
+		}
+	}
+}
+
+// jsonFindings creates a finding for every evidence of a match result
+// with file paths relative to the source path when possible
+func jsonFindings(sourcePath string, noSnippets bool,
+	signatureMatchResult *common.EnrichedSignatureMatchResult,
+) []JSONFinding {
+	signature := jsonSignature(signatureMatchResult.MatchedSignature)
+
+	filePath, ok := common.RelativeSourcePath(sourcePath, signatureMatchResult.FilePath)
+	if !ok {
+		filePath = filepath.ToSlash(signatureMatchResult.FilePath)
+	}
+
+	var suppression *JSONSuppression
+	if signatureMatchResult.Suppression != nil {
+		suppression = &JSONSuppression{
+			Source: signatureMatchResult.Suppression.Source,
+			Reason: signatureMatchResult.Suppression.Reason,
+		}
+	}
+
+	findings := []JSONFinding{}
+	for i, condition := range signatureMatchResult.MatchedConditions {
+		jsonCondition := JSONCondition{}
+		if condition.Condition != nil {
+			jsonCondition.Type = condition.Condition.Type
+			jsonCondition.Value = condition.Condition.Value
+		}
+
+		for j := range condition.Evidences {
+			evidenceMetadata := signatureMatchResult.Metadata(i, j)
+
+			callerContent := evidenceMetadata.CallerIdentifierContent
+			if noSnippets {
+				callerContent = ""
+			}
+
+			finding := JSONFinding{
+				Signature:       signature,
+				Condition:       jsonCondition,
+				Language:        string(signatureMatchResult.MatchedLanguageCode),
+				FilePath:        filePath,
+				CallerContent:   callerContent,
+				CallerNamespace: evidenceMetadata.CallerNamespace,
+				CalleeNamespace: evidenceMetadata.CalleeNamespace,
+				Suppression:     suppression,
+				DiffStatus:      string(signatureMatchResult.DiffStatus),
+			}
+
+			if evidenceMetadata.CallerIdentifierMetadata != nil {
+				finding.Range = &JSONRange{
+					Start: JSONPosition{
+						Line:   int(evidenceMetadata.CallerIdentifierMetadata.StartLine) + 1,
+						Column: int(evidenceMetadata.CallerIdentifierMetadata.StartColumn) + 1,
+					},
+					End: JSONPosition{
+						Line:   int(evidenceMetadata.CallerIdentifierMetadata.EndLine) + 1,
+						Column: int(evidenceMetadata.CallerIdentifierMetadata.EndColumn) + 1,
+					},
+				}
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+func (r *JSONReporter) Finish() error {
+	report := r.buildReport()
+
+	log.Infof("Writing JSON report to %s", r.config.Path)
+
+	fd, err := os.Create(r.config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON report file: %w", err)
+	}
+
+	defer func() {
+		if err := fd.Close(); err != nil {
+			log.Errorf("Failed to close file %s: %v", r.config.Path, err)
+		}
+	}()
+
+	encoder := json.NewEncoder(fd)
+	encoder.SetIndent("", "  ")
+
+	err = encoder.Encode(report)
+	if err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+
+	fmt.Printf("📄 JSON report saved at %s\n", r.config.Path)
+
+	return nil
+}
+
+// buildReport creates the JSON report with findings sorted
+// for deterministic output
+func (r *JSONReporter) buildReport() JSONReport {
+	findings := make([]JSONFinding, len(r.findings))
+	copy(findings, r.findings)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return jsonFindingSortKey(findings[i]) < jsonFindingSortKey(findings[j])
+	})
+
+	var notAnalyzed []JSONSkippedFile
+	if len(r.notAnalyzed) > 0 {
+		notAnalyzed = make([]JSONSkippedFile, len(r.notAnalyzed))
+		copy(notAnalyzed, r.notAnalyzed)
+
+		sort.SliceStable(notAnalyzed, func(i, j int) bool {
+			return notAnalyzed[i].FilePath < notAnalyzed[j].FilePath
+		})
+	}
+
+	var diagnostics []JSONDiagnostic
+	if len(r.diagnostics) > 0 {
+		diagnostics = make([]JSONDiagnostic, len(r.diagnostics))
+		copy(diagnostics, r.diagnostics)
+
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			return diagnostics[i].FilePath < diagnostics[j].FilePath
+		})
+	}
+
+	return JSONReport{
+		SchemaVersion: JSONReportSchemaVersion,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		Tool: JSONTool{
+			Name:    r.config.Tool.Name,
+			Version: r.config.Tool.Version,
+			Purl:    r.config.Tool.Purl,
+		},
+		SourcePath:  r.config.SourcePath,
+		Findings:    findings,
+		NotAnalyzed: notAnalyzed,
+		Diagnostics: diagnostics,
+	}
+}
+
+func jsonSignature(signature *callgraphv1.Signature) JSONSignature {
+	return JSONSignature{
+		ID:          signature.GetId(),
+		Description: signature.GetDescription(),
+		Vendor:      signature.GetVendor(),
+		Product:     signature.GetProduct(),
+		Service:     signature.GetService(),
+		Tags:        signature.GetTags(),
+	}
+}
+
+func jsonFindingSortKey(finding JSONFinding) string {
+	line, column := 0, 0
+	if finding.Range != nil {
+		line, column = finding.Range.Start.Line, finding.Range.Start.Column
+	}
+
+	return fmt.Sprintf("%s|%s|%010d|%010d|%s|%s", finding.Signature.ID, finding.FilePath,
+		line, column, finding.Condition.Type, finding.Condition.Value)
+}
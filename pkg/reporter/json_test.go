@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONReporter(t *testing.T) {
+	_, err := NewJSONReporter(JSONReporterConfig{})
+	assert.Error(t, err, "path is required")
+
+	reporter, err := NewJSONReporter(JSONReporterConfig{Path: "report.json"})
+	require.NoError(t, err)
+	assert.Equal(t, "json", reporter.Name())
+}
+
+func TestJSONReporter_Finish(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.json")
+
+	reporter, err := NewJSONReporter(JSONReporterConfig{
+		Tool:       common.ToolMetadata{Name: "xbom", Version: "1.0.0"},
+		Path:       outputPath,
+		SourcePath: "/src",
+	})
+	require.NoError(t, err)
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"openai.client": {
+				{
+					SignatureMatchResult: callgraph.SignatureMatchResult{
+						FilePath: "/src/app/main.py",
+						MatchedSignature: &callgraphv1.Signature{
+							Id:          "openai.client",
+							Description: "OpenAI client",
+							Vendor:      "OpenAI",
+							Product:     "OpenAI",
+							Service:     "AI client",
+							Tags:        []string{"ai", "llm"},
+						},
+						MatchedLanguageCode: core.LanguageCodePython,
+						MatchedConditions: []callgraph.MatchedCondition{
+							{
+								Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+									Type:  "call",
+									Value: "openai.*",
+								},
+								Evidences: []callgraph.MatchedEvidence{{}},
+							},
+						},
+					},
+				},
+			},
+			"golang.crypto.hash": {
+				{
+					SignatureMatchResult: callgraph.SignatureMatchResult{
+						FilePath: "/other/hash.go",
+						MatchedSignature: &callgraphv1.Signature{
+							Id: "golang.crypto.hash",
+						},
+						MatchedLanguageCode: core.LanguageCodeGo,
+						MatchedConditions: []callgraph.MatchedCondition{
+							{
+								Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+									Type:  "call",
+									Value: "crypto/sha256/Sum256",
+								},
+								Evidences: []callgraph.MatchedEvidence{{}, {}},
+							},
+						},
+					},
+				},
+			},
+		},
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/web/bundle.min.js", Reason: common.SkipReasonFileSize},
+			{Path: "/src/app/generated.py", Reason: common.SkipReasonTimeout},
+		},
+		FailedFiles: []common.FailedFile{
+			{Path: "/src/app/broken.py", Err: errors.New("failed to parse source file")},
+		},
+	}
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal(content, &report))
+
+	assert.Equal(t, JSONReportSchemaVersion, report.SchemaVersion)
+	assert.Equal(t, "xbom", report.Tool.Name)
+	assert.Equal(t, "/src", report.SourcePath)
+	assert.NotEmpty(t, report.GeneratedAt)
+
+	// One finding per evidence, sorted by signature ID
+	require.Len(t, report.Findings, 3)
+	assert.Equal(t, "golang.crypto.hash", report.Findings[0].Signature.ID)
+	assert.Equal(t, "/other/hash.go", report.Findings[0].FilePath)
+	assert.Equal(t, "openai.client", report.Findings[2].Signature.ID)
+
+	finding := report.Findings[2]
+	assert.Equal(t, "app/main.py", finding.FilePath)
+	assert.Equal(t, "python", finding.Language)
+	assert.Equal(t, JSONCondition{Type: "call", Value: "openai.*"}, finding.Condition)
+	assert.Equal(t, "OpenAI", finding.Signature.Vendor)
+	assert.Equal(t, []string{"ai", "llm"}, finding.Signature.Tags)
+	assert.Nil(t, finding.Range)
+
+	// Files not analyzed are sorted by file path
+	assert.Equal(t, []JSONSkippedFile{
+		{FilePath: "app/generated.py", Reason: "timeout"},
+		{FilePath: "web/bundle.min.js", Reason: "file-size"},
+	}, report.NotAnalyzed)
+
+	assert.Equal(t, []JSONDiagnostic{
+		{FilePath: "app/broken.py", Error: "failed to parse source file"},
+	}, report.Diagnostics)
+}
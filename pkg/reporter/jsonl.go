@@ -0,0 +1,221 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+type JSONLReporterConfig struct {
+	// Path defines the output file path
+	Path string
+
+	// SourcePath is the scanned directory. File paths are
+	// reported relative to this path when possible.
+	SourcePath string
+
+	// NoSnippets omits the caller content of findings
+	NoSnippets bool
+}
+
+// Types of the records of the JSONL report
+const (
+	JSONLRecordTypeFinding     = "finding"
+	JSONLRecordTypeNotAnalyzed = "not_analyzed"
+	JSONLRecordTypeDiagnostic  = "diagnostic"
+)
+
+// JSONLFinding is a finding of the JSON report written as a JSONL record
+type JSONLFinding struct {
+	Type string `json:"type"`
+	JSONFinding
+}
+
+// JSONLSkippedFile is a file not analyzed written as a JSONL record
+type JSONLSkippedFile struct {
+	Type string `json:"type"`
+	JSONSkippedFile
+}
+
+// JSONLDiagnostic is a file whose analysis failed written as a JSONL record
+type JSONLDiagnostic struct {
+	Type string `json:"type"`
+	JSONDiagnostic
+}
+
+// JSONLReporter streams findings as JSON Lines, one finding of the JSON report
+// per line, as soon as the file of the finding is analysed. Nothing is buffered
+// beyond the findings of a file, so it is suitable for very large scans. Files
+// not analyzed and diagnostics of failed files are written after all findings.
+//
+// Unlike every other report, findings are written in the order files are
+// analysed, which is not deterministic when files are analysed concurrently.
+type JSONLReporter struct {
+	config   JSONLReporterConfig
+	fd       *os.File
+	writer   *bufio.Writer
+	closed   bool
+	findings int
+}
+
+var _ StreamingReporter = (*JSONLReporter)(nil)
+
+func NewJSONLReporter(config JSONLReporterConfig) (*JSONLReporter, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("JSONL report path is required")
+	}
+
+	return &JSONLReporter{
+		config: config,
+	}, nil
+}
+
+func (r *JSONLReporter) Name() string {
+	return "jsonl"
+}
+
+func (r *JSONLReporter) RecordMatchResults(results []common.EnrichedSignatureMatchResult) error {
+	err := r.open()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(r.writer)
+	for i := range results {
+		for _, finding := range jsonFindings(r.config.SourcePath, r.config.NoSnippets, &results[i]) {
+			err := encoder.Encode(JSONLFinding{Type: JSONLRecordTypeFinding, JSONFinding: finding})
+			if err != nil {
+				return fmt.Errorf("failed to write JSONL report: %w", err)
+			}
+
+			r.findings++
+		}
+	}
+
+	// Flush the findings of every file so that consumers can tail the report
+	err = r.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write JSONL report: %w", err)
+	}
+
+	return nil
+}
+
+// RecordCodeAnalysisFindings writes the files not analyzed and the diagnostics,
+// sorted by file path, as the findings are already streamed
+func (r *JSONLReporter) RecordCodeAnalysisFindings(findings *common.CodeAnalysisFindings) error {
+	err := r.open()
+	if err != nil {
+		return err
+	}
+
+	notAnalyzed := make([]JSONSkippedFile, 0, len(findings.SkippedFiles))
+	for _, skippedFile := range findings.SkippedFiles {
+		notAnalyzed = append(notAnalyzed, JSONSkippedFile{
+			FilePath: reportFilePath(r.config.SourcePath, skippedFile.Path),
+			Reason:   string(skippedFile.Reason),
+		})
+	}
+
+	sort.SliceStable(notAnalyzed, func(i, j int) bool {
+		return notAnalyzed[i].FilePath < notAnalyzed[j].FilePath
+	})
+
+	encoder := json.NewEncoder(r.writer)
+	for _, skippedFile := range notAnalyzed {
+		err := encoder.Encode(JSONLSkippedFile{Type: JSONLRecordTypeNotAnalyzed, JSONSkippedFile: skippedFile})
+		if err != nil {
+			return fmt.Errorf("failed to write JSONL report: %w", err)
+		}
+	}
+
+	diagnostics := make([]JSONDiagnostic, 0, len(findings.FailedFiles))
+	for _, failedFile := range findings.FailedFiles {
+		diagnostics = append(diagnostics, JSONDiagnostic{
+			FilePath: reportFilePath(r.config.SourcePath, failedFile.Path),
+			Error:    failedFile.Err.Error(),
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].FilePath < diagnostics[j].FilePath
+	})
+
+	for _, diagnostic := range diagnostics {
+		err := encoder.Encode(JSONLDiagnostic{Type: JSONLRecordTypeDiagnostic, JSONDiagnostic: diagnostic})
+		if err != nil {
+			return fmt.Errorf("failed to write JSONL report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *JSONLReporter) Finish() error {
+	// The report is created even without any finding
+	err := r.open()
+	if err != nil {
+		return err
+	}
+
+	err = r.Close()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Wrote %d findings to JSONL report %s", r.findings, r.config.Path)
+	fmt.Printf("📄 JSONL report saved at %s\n", r.config.Path)
+
+	return nil
+}
+
+// Close flushes and closes the report file when open. It is safe to call more
+// than once, so that the report file is not leaked when the analysis fails
+// before Finish.
+func (r *JSONLReporter) Close() error {
+	if r.fd == nil || r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	err := r.writer.Flush()
+	if err != nil {
+		_ = r.fd.Close()
+		return fmt.Errorf("failed to write JSONL report: %w", err)
+	}
+
+	err = r.fd.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close JSONL report file: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JSONLReporter) open() error {
+	if r.closed {
+		return fmt.Errorf("JSONL report %s is already closed", r.config.Path)
+	}
+
+	if r.fd != nil {
+		return nil
+	}
+
+	log.Infof("Writing JSONL report to %s", r.config.Path)
+
+	fd, err := os.Create(r.config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSONL report file: %w", err)
+	}
+
+	r.fd = fd
+	r.writer = bufio.NewWriter(fd)
+
+	return nil
+}
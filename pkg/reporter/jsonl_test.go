@@ -0,0 +1,269 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readJSONLRecords returns the lines of a JSONL report having the record type
+func readJSONLRecords(t *testing.T, path, recordType string) [][]byte {
+	t.Helper()
+
+	fd, err := os.Open(path)
+	require.NoError(t, err)
+	defer fd.Close()
+
+	records := [][]byte{}
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		var record struct {
+			Type string `json:"type"`
+		}
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		require.NotEmpty(t, record.Type, "every record has a type")
+
+		if record.Type == recordType {
+			records = append(records, append([]byte{}, scanner.Bytes()...))
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func readJSONLFindings(t *testing.T, path string) []JSONFinding {
+	t.Helper()
+
+	findings := []JSONFinding{}
+	for _, record := range readJSONLRecords(t, path, JSONLRecordTypeFinding) {
+		var finding JSONFinding
+		require.NoError(t, json.Unmarshal(record, &finding))
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+func TestNewJSONLReporter(t *testing.T) {
+	_, err := NewJSONLReporter(JSONLReporterConfig{})
+	assert.Error(t, err, "path is required")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: "report.jsonl"})
+	require.NoError(t, err)
+	assert.Equal(t, "jsonl", reporter.Name())
+}
+
+func TestJSONLReporter(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{
+		Path:       outputPath,
+		SourcePath: "/src",
+	})
+	require.NoError(t, err)
+
+	signature := &callgraphv1.Signature{Id: "golang.crypto.hash", Tags: []string{"crypto"}}
+	condition := &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+		Type:  "call",
+		Value: "crypto/sha256/Sum256",
+	}
+
+	results := []common.EnrichedSignatureMatchResult{
+		{
+			SignatureMatchResult: callgraph.SignatureMatchResult{
+				FilePath:            "/src/hash.go",
+				MatchedSignature:    signature,
+				MatchedLanguageCode: core.LanguageCodeGo,
+				MatchedConditions: []callgraph.MatchedCondition{
+					{Condition: condition, Evidences: []callgraph.MatchedEvidence{{}, {}}},
+				},
+			},
+			EvidenceMetadata: [][]callgraph.EvidenceMetadata{
+				{
+					{CallerIdentifierContent: "sha256.Sum256(a)"},
+					{CallerIdentifierContent: "sha256.Sum256(b)"},
+				},
+			},
+		},
+		{
+			SignatureMatchResult: callgraph.SignatureMatchResult{
+				FilePath:            "/src/hash.go",
+				MatchedSignature:    signature,
+				MatchedLanguageCode: core.LanguageCodeGo,
+				MatchedConditions: []callgraph.MatchedCondition{
+					{Condition: condition, Evidences: []callgraph.MatchedEvidence{{}}},
+				},
+			},
+			Suppression: &common.Suppression{Source: "inline", Reason: "checksum only"},
+		},
+	}
+
+	// Findings are written as soon as they are recorded
+	require.NoError(t, reporter.RecordMatchResults(results))
+
+	findings := readJSONLFindings(t, outputPath)
+	require.Len(t, findings, 3)
+	assert.Equal(t, "hash.go", findings[0].FilePath)
+	assert.Equal(t, "sha256.Sum256(a)", findings[0].CallerContent)
+	assert.Equal(t, "sha256.Sum256(b)", findings[1].CallerContent)
+	assert.Nil(t, findings[1].Suppression)
+	assert.Equal(t, &JSONSuppression{Source: "inline", Reason: "checksum only"}, findings[2].Suppression)
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(&common.CodeAnalysisFindings{}))
+	require.NoError(t, reporter.Finish())
+	assert.Len(t, readJSONLFindings(t, outputPath), 3)
+}
+
+func TestJSONLReporterWithoutFindings(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: outputPath})
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.Finish())
+	assert.Empty(t, readJSONLFindings(t, outputPath))
+}
+
+func TestJSONLReporterWithoutSnippets(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: outputPath, NoSnippets: true})
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.RecordMatchResults([]common.EnrichedSignatureMatchResult{
+		{
+			SignatureMatchResult: callgraph.SignatureMatchResult{
+				FilePath:            "/src/hash.go",
+				MatchedSignature:    &callgraphv1.Signature{Id: "golang.crypto.hash"},
+				MatchedLanguageCode: core.LanguageCodeGo,
+				MatchedConditions: []callgraph.MatchedCondition{
+					{
+						Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{},
+						Evidences: []callgraph.MatchedEvidence{{}},
+					},
+				},
+			},
+			EvidenceMetadata: [][]callgraph.EvidenceMetadata{
+				{
+					{
+						CallerIdentifierContent:  "sha256.Sum256(a)",
+						CallerIdentifierMetadata: &callgraph.TreeNodeMetadata{StartLine: 9, EndLine: 9},
+					},
+				},
+			},
+		},
+	}))
+	require.NoError(t, reporter.Finish())
+
+	findings := readJSONLFindings(t, outputPath)
+	require.Len(t, findings, 1)
+	assert.Empty(t, findings[0].CallerContent)
+	require.NotNil(t, findings[0].Range)
+	assert.Equal(t, 10, findings[0].Range.Start.Line)
+}
+
+func TestJSONLReporterNotAnalyzed(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: outputPath, SourcePath: "/src"})
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(&common.CodeAnalysisFindings{
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/b.js", Reason: common.SkipReasonTimeout},
+			{Path: "/src/a.js", Reason: common.SkipReasonFileSize},
+		},
+	}))
+	require.NoError(t, reporter.Finish())
+
+	notAnalyzed := []JSONSkippedFile{}
+	for _, record := range readJSONLRecords(t, outputPath, JSONLRecordTypeNotAnalyzed) {
+		var skippedFile JSONSkippedFile
+		require.NoError(t, json.Unmarshal(record, &skippedFile))
+		notAnalyzed = append(notAnalyzed, skippedFile)
+	}
+
+	assert.Equal(t, []JSONSkippedFile{
+		{FilePath: "a.js", Reason: string(common.SkipReasonFileSize)},
+		{FilePath: "b.js", Reason: string(common.SkipReasonTimeout)},
+	}, notAnalyzed)
+}
+
+func TestJSONLReporterClose(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: outputPath})
+	require.NoError(t, err)
+
+	// Closing before anything is recorded does not create the report
+	require.NoError(t, reporter.Close())
+	assert.NoFileExists(t, outputPath)
+
+	require.NoError(t, reporter.RecordMatchResults([]common.EnrichedSignatureMatchResult{
+		{
+			SignatureMatchResult: callgraph.SignatureMatchResult{
+				FilePath:            "/src/hash.go",
+				MatchedSignature:    &callgraphv1.Signature{Id: "golang.crypto.hash"},
+				MatchedLanguageCode: core.LanguageCodeGo,
+				MatchedConditions: []callgraph.MatchedCondition{
+					{
+						Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{},
+						Evidences: []callgraph.MatchedEvidence{{}},
+					},
+				},
+			},
+		},
+	}))
+
+	// The findings streamed so far are kept when the analysis fails before Finish
+	require.NoError(t, reporter.Close())
+	require.NoError(t, reporter.Close())
+	assert.Len(t, readJSONLFindings(t, outputPath), 1)
+
+	assert.Error(t, reporter.RecordMatchResults(nil), "report is closed")
+}
+
+func TestJSONLReporterDiagnostics(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewJSONLReporter(JSONLReporterConfig{Path: outputPath, SourcePath: "/src"})
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(&common.CodeAnalysisFindings{
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/large.js", Reason: common.SkipReasonFileSize},
+		},
+		FailedFiles: []common.FailedFile{
+			{Path: "/src/b.py", Err: errors.New("failed to parse")},
+			{Path: "/src/a.py", Err: errors.New("failed to read")},
+		},
+	}))
+	require.NoError(t, reporter.Finish())
+
+	diagnostics := []JSONDiagnostic{}
+	for _, record := range readJSONLRecords(t, outputPath, JSONLRecordTypeDiagnostic) {
+		var diagnostic JSONDiagnostic
+		require.NoError(t, json.Unmarshal(record, &diagnostic))
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	assert.Equal(t, []JSONDiagnostic{
+		{FilePath: "a.py", Error: "failed to read"},
+		{FilePath: "b.py", Error: "failed to parse"},
+	}, diagnostics)
+
+	assert.Len(t, readJSONLRecords(t, outputPath, JSONLRecordTypeNotAnalyzed), 1)
+}
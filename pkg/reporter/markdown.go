@@ -21,6 +21,13 @@ type MarkdownReporterConfig struct {
 	SnippetMaxBytes     int    // Max total bytes for snippet (default: 5120 = 5KB)
 	SnippetMaxLineChars int    // Max characters per line (default: 500)
 
+	// NoSnippets omits code snippets, findings are reported with their location only
+	NoSnippets bool
+
+	// NoDefaults uses the config as is, so that zero context lines, no size limit
+	// and no sections can be set. Defaults replace zero values otherwise.
+	NoDefaults bool
+
 	// Boolean flags for section control (all true by default)
 	ShowExecutiveSummary  bool // Show executive summary section
 	ShowStatistics        bool // Show statistics section
@@ -29,6 +36,23 @@ type MarkdownReporterConfig struct {
 	ShowDetailedFindings  bool // Show detailed findings section
 }
 
+// DefaultMarkdownReporterConfig returns the config with snippets of 3 context lines
+// limited to 5KB and 500 characters per line, and all sections shown. The config
+// is also applied by NewMarkdownReporter to zero values unless NoDefaults is set.
+func DefaultMarkdownReporterConfig() MarkdownReporterConfig {
+	return MarkdownReporterConfig{
+		SnippetBeforeLines:    3,
+		SnippetAfterLines:     3,
+		SnippetMaxBytes:       5120, // 5KB
+		SnippetMaxLineChars:   500,
+		ShowExecutiveSummary:  true,
+		ShowStatistics:        true,
+		ShowTopSignatures:     true,
+		ShowLanguageBreakdown: true,
+		ShowDetailedFindings:  true,
+	}
+}
+
 type MarkdownReporter struct {
 	config     MarkdownReporterConfig
 	findings   *common.CodeAnalysisFindings
@@ -58,6 +82,7 @@ type fileOccurrence struct {
 
 type matchDetail struct {
 	Condition string
+	Line      int // 1-based line of the match, zero when unknown
 	Snippet   *snippetInfo
 }
 
@@ -74,45 +99,57 @@ var _ Reporter = (*MarkdownReporter)(nil)
 var markdownTemplateFS embed.FS
 
 func NewMarkdownReporter(config MarkdownReporterConfig) (*MarkdownReporter, error) {
-	// Set defaults for snippet configuration
+	err := validateSnippetOptions(config.SnippetBeforeLines, config.SnippetAfterLines,
+		config.SnippetMaxBytes, config.SnippetMaxLineChars)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.NoDefaults {
+		config = withMarkdownDefaults(config)
+	}
+
+	return &MarkdownReporter{
+		config: config,
+		statistics: &reportStatistics{
+			filesAffected:   make(map[string]bool),
+			languageCounts:  make(map[string]int),
+			signatureCounts: make(map[string]int),
+		},
+	}, nil
+}
+
+// withMarkdownDefaults sets the zero snippet options to their default. All sections
+// are shown when none is, since they are then likely zero values.
+func withMarkdownDefaults(config MarkdownReporterConfig) MarkdownReporterConfig {
+	defaults := DefaultMarkdownReporterConfig()
+
 	if config.SnippetBeforeLines == 0 {
-		config.SnippetBeforeLines = 3
+		config.SnippetBeforeLines = defaults.SnippetBeforeLines
 	}
 	if config.SnippetAfterLines == 0 {
-		config.SnippetAfterLines = 3
+		config.SnippetAfterLines = defaults.SnippetAfterLines
 	}
 	if config.SnippetMaxBytes == 0 {
-		config.SnippetMaxBytes = 5120 // 5KB
+		config.SnippetMaxBytes = defaults.SnippetMaxBytes
 	}
 	if config.SnippetMaxLineChars == 0 {
-		config.SnippetMaxLineChars = 500
+		config.SnippetMaxLineChars = defaults.SnippetMaxLineChars
 	}
 
-	// Set defaults for section visibility - default all to enabled
-	// If at least one section flag is explicitly true, assume user is configuring sections
-	// Otherwise, if all are false (zero values), enable all sections by default
 	hasAnySectionEnabled := config.ShowExecutiveSummary || config.ShowStatistics ||
 		config.ShowTopSignatures || config.ShowLanguageBreakdown ||
 		config.ShowDetailedFindings
 
 	if !hasAnySectionEnabled {
-		// All are false (likely zero values) - enable all sections by default
-		config.ShowExecutiveSummary = true
-		config.ShowStatistics = true
-		config.ShowTopSignatures = true
-		config.ShowLanguageBreakdown = true
-		config.ShowDetailedFindings = true
+		config.ShowExecutiveSummary = defaults.ShowExecutiveSummary
+		config.ShowStatistics = defaults.ShowStatistics
+		config.ShowTopSignatures = defaults.ShowTopSignatures
+		config.ShowLanguageBreakdown = defaults.ShowLanguageBreakdown
+		config.ShowDetailedFindings = defaults.ShowDetailedFindings
 	}
-	// If any section is enabled, respect the user's configuration as-is
 
-	return &MarkdownReporter{
-		config: config,
-		statistics: &reportStatistics{
-			filesAffected:   make(map[string]bool),
-			languageCounts:  make(map[string]int),
-			signatureCounts: make(map[string]int),
-		},
-	}, nil
+	return config
 }
 
 func (r *MarkdownReporter) Name() string {
@@ -183,7 +220,41 @@ func (r *MarkdownReporter) prepareReportData() map[string]interface{} {
 		"LanguageBreakdown":     r.prepareLanguageBreakdown(),
 		"DetailedFindings":      r.prepareDetailedFindings(),
 		"HasFindings":           r.statistics.totalFindings > 0,
+		"NotAnalyzed":           r.prepareNotAnalyzed(),
+		"Diagnostics":           r.prepareDiagnostics(),
+	}
+}
+
+func (r *MarkdownReporter) prepareNotAnalyzed() []map[string]interface{} {
+	if r.findings == nil {
+		return []map[string]interface{}{}
+	}
+
+	result := make([]map[string]interface{}, len(r.findings.SkippedFiles))
+	for i, skippedFile := range r.findings.SkippedFiles {
+		result[i] = map[string]interface{}{
+			"FilePath": skippedFile.Path,
+			"Reason":   skipReasonDescription(skippedFile.Reason),
+		}
 	}
+
+	return result
+}
+
+func (r *MarkdownReporter) prepareDiagnostics() []map[string]interface{} {
+	if r.findings == nil {
+		return []map[string]interface{}{}
+	}
+
+	result := make([]map[string]interface{}, len(r.findings.FailedFiles))
+	for i, failedFile := range r.findings.FailedFiles {
+		result[i] = map[string]interface{}{
+			"FilePath": failedFile.Path,
+			"Error":    strings.NewReplacer("|", "\\|", "\n", " ").Replace(failedFile.Err.Error()),
+		}
+	}
+
+	return result
 }
 
 func (r *MarkdownReporter) prepareStatistics() map[string]interface{} {
@@ -276,9 +347,9 @@ func (r *MarkdownReporter) prepareDetailedFindings() []signatureDetail {
 				Matches:  []matchDetail{},
 			}
 
-			for _, condition := range signatureMatchResult.MatchedConditions {
-				for _, evidence := range condition.Evidences {
-					evidenceMetadata := evidence.Metadata(signatureMatchResult.TreeData)
+			for i, condition := range signatureMatchResult.MatchedConditions {
+				for j := range condition.Evidences {
+					evidenceMetadata := signatureMatchResult.Metadata(i, j)
 
 					conditionStr := fmt.Sprintf("%s: %s",
 						condition.Condition.Type,
@@ -288,8 +359,12 @@ func (r *MarkdownReporter) prepareDetailedFindings() []signatureDetail {
 						Condition: conditionStr,
 					}
 
-					// Extract snippet if available
 					if evidenceMetadata.CallerIdentifierMetadata != nil {
+						match.Line = int(evidenceMetadata.CallerIdentifierMetadata.StartLine) + 1
+					}
+
+					// Extract snippet if available
+					if evidenceMetadata.CallerIdentifierMetadata != nil && !r.config.NoSnippets {
 						startLine := int(evidenceMetadata.CallerIdentifierMetadata.StartLine)
 						endLine := int(evidenceMetadata.CallerIdentifierMetadata.EndLine)
 
@@ -1,6 +1,7 @@
 package reporter
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,10 +16,24 @@ import (
 )
 
 func TestNewMarkdownReporter_ConfigDefaults(t *testing.T) {
+	defaults := DefaultMarkdownReporterConfig()
+	assert.Equal(t, MarkdownReporterConfig{
+		SnippetBeforeLines:    3,
+		SnippetAfterLines:     3,
+		SnippetMaxBytes:       5120,
+		SnippetMaxLineChars:   500,
+		ShowExecutiveSummary:  true,
+		ShowStatistics:        true,
+		ShowTopSignatures:     true,
+		ShowLanguageBreakdown: true,
+		ShowDetailedFindings:  true,
+	}, defaults)
+
 	tests := []struct {
 		name           string
 		inputConfig    MarkdownReporterConfig
 		expectedConfig MarkdownReporterConfig
+		expectErr      bool
 	}{
 		{
 			name: "all defaults applied",
@@ -80,11 +95,60 @@ func TestNewMarkdownReporter_ConfigDefaults(t *testing.T) {
 				ShowDetailedFindings:  false,
 			},
 		},
+		{
+			name: "zero values are kept without defaults",
+			inputConfig: MarkdownReporterConfig{
+				OutputPath: "test.md",
+				NoDefaults: true,
+			},
+			expectedConfig: MarkdownReporterConfig{
+				OutputPath: "test.md",
+				NoDefaults: true,
+			},
+		},
+		{
+			name: "no snippets",
+			inputConfig: MarkdownReporterConfig{
+				OutputPath:           "test.md",
+				NoSnippets:           true,
+				ShowDetailedFindings: true,
+			},
+			expectedConfig: MarkdownReporterConfig{
+				OutputPath:           "test.md",
+				SnippetBeforeLines:   3,
+				SnippetAfterLines:    3,
+				SnippetMaxBytes:      5120,
+				SnippetMaxLineChars:  500,
+				NoSnippets:           true,
+				ShowDetailedFindings: true,
+			},
+		},
+		{
+			name: "negative context lines",
+			inputConfig: MarkdownReporterConfig{
+				OutputPath:         "test.md",
+				SnippetBeforeLines: -1,
+			},
+			expectErr: true,
+		},
+		{
+			name: "negative max bytes",
+			inputConfig: MarkdownReporterConfig{
+				OutputPath:      "test.md",
+				SnippetMaxBytes: -1,
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reporter, err := NewMarkdownReporter(tt.inputConfig)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedConfig, reporter.config)
 		})
@@ -359,9 +423,10 @@ func TestMarkdownReporter_GenerateReport_WithFindings(t *testing.T) {
 	tempDir := t.TempDir()
 	outputPath := filepath.Join(tempDir, "report.md")
 
-	reporter, err := NewMarkdownReporter(MarkdownReporterConfig{
-		OutputPath: outputPath,
-	})
+	config := DefaultMarkdownReporterConfig()
+	config.OutputPath = outputPath
+
+	reporter, err := NewMarkdownReporter(config)
 	require.NoError(t, err)
 
 	// Create test findings
@@ -422,9 +487,10 @@ func TestMarkdownReporter_GenerateReport_NoFindings(t *testing.T) {
 	tempDir := t.TempDir()
 	outputPath := filepath.Join(tempDir, "report.md")
 
-	reporter, err := NewMarkdownReporter(MarkdownReporterConfig{
-		OutputPath: outputPath,
-	})
+	config := DefaultMarkdownReporterConfig()
+	config.OutputPath = outputPath
+
+	reporter, err := NewMarkdownReporter(config)
 	require.NoError(t, err)
 
 	// Record empty findings
@@ -461,6 +527,59 @@ func TestMarkdownReporter_GenerateReport_NoFindings(t *testing.T) {
 	assert.True(t, containsNoFindingsIndicator, "Report should indicate no findings were found")
 }
 
+func TestMarkdownReporter_GenerateReport_NotAnalyzed(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.md")
+
+	config := DefaultMarkdownReporterConfig()
+	config.OutputPath = outputPath
+
+	reporter, err := NewMarkdownReporter(config)
+	require.NoError(t, err)
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{},
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/web/bundle.min.js", Reason: common.SkipReasonFileSize},
+		},
+	}
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "## Not Analyzed")
+	assert.Contains(t, string(content), "| `/src/web/bundle.min.js` | file size exceeds the analysis budget |")
+}
+
+func TestMarkdownReporter_GenerateReport_Diagnostics(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.md")
+
+	config := DefaultMarkdownReporterConfig()
+	config.OutputPath = outputPath
+
+	reporter, err := NewMarkdownReporter(config)
+	require.NoError(t, err)
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{},
+		FailedFiles: []common.FailedFile{
+			{Path: "/src/app/broken.py", Err: errors.New("failed to parse source file: a | b")},
+		},
+	}
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "## Diagnostics")
+	assert.Contains(t, string(content), "| `/src/app/broken.py` | failed to parse source file: a \\| b |")
+	assert.NotContains(t, string(content), "## Not Analyzed")
+}
+
 func TestMarkdownReporter_GenerateReport_SectionVisibility(t *testing.T) {
 	tests := []struct {
 		name                      string
@@ -548,9 +667,10 @@ func TestMarkdownTemplate_ValidMarkdownStructure(t *testing.T) {
 	tempDir := t.TempDir()
 	outputPath := filepath.Join(tempDir, "report.md")
 
-	reporter, err := NewMarkdownReporter(MarkdownReporterConfig{
-		OutputPath: outputPath,
-	})
+	config := DefaultMarkdownReporterConfig()
+	config.OutputPath = outputPath
+
+	reporter, err := NewMarkdownReporter(config)
 	require.NoError(t, err)
 
 	findings := &common.CodeAnalysisFindings{
@@ -601,3 +721,97 @@ func TestMarkdownTemplate_ValidMarkdownStructure(t *testing.T) {
 	assert.Contains(t, contentStr, "python", "Should contain language")
 	assert.Contains(t, contentStr, "/test/file.py", "Should contain file path")
 }
+
+func TestMarkdownReporter_GenerateReport_Snippets(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "app.py")
+	require.NoError(t, os.WriteFile(sourcePath,
+		[]byte("import hashlib\n\nline_three = 3\nline_four = 4\nhashlib.md5(b\"data\")\nline_six = 6\n"), 0o644))
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"python.crypto.md5": {
+				{
+					SignatureMatchResult: callgraph.SignatureMatchResult{
+						FilePath: sourcePath,
+						MatchedSignature: &callgraphv1.Signature{
+							Id: "python.crypto.md5",
+						},
+						MatchedLanguageCode: core.LanguageCodePython,
+						MatchedConditions: []callgraph.MatchedCondition{
+							{
+								Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+									Type:  "call",
+									Value: "hashlib/md5",
+								},
+								Evidences: []callgraph.MatchedEvidence{{}},
+							},
+						},
+					},
+					EvidenceMetadata: [][]callgraph.EvidenceMetadata{
+						{
+							{CallerIdentifierMetadata: &callgraph.TreeNodeMetadata{StartLine: 4, EndLine: 4}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		configure   func(config *MarkdownReporterConfig)
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:        "default context",
+			configure:   func(config *MarkdownReporterConfig) {},
+			expected:    []string{"**Code Snippet:**", "line_three = 3", "hashlib.md5", "line_six = 6"},
+			notExpected: []string{"**Line:**"},
+		},
+		{
+			name: "zero context lines",
+			configure: func(config *MarkdownReporterConfig) {
+				config.SnippetBeforeLines = 0
+				config.SnippetAfterLines = 0
+				config.NoDefaults = true
+			},
+			expected:    []string{"**Code Snippet:**", "hashlib.md5"},
+			notExpected: []string{"line_four = 4", "line_six = 6"},
+		},
+		{
+			name: "no snippets",
+			configure: func(config *MarkdownReporterConfig) {
+				config.NoSnippets = true
+			},
+			expected:    []string{"**Line:** 5"},
+			notExpected: []string{"**Code Snippet:**", "hashlib.md5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultMarkdownReporterConfig()
+			config.OutputPath = filepath.Join(t.TempDir(), "report.md")
+			tt.configure(&config)
+
+			reporter, err := NewMarkdownReporter(config)
+			require.NoError(t, err)
+
+			require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+			require.NoError(t, reporter.Finish())
+
+			content, err := os.ReadFile(config.OutputPath)
+			require.NoError(t, err)
+
+			for _, expected := range tt.expected {
+				assert.Contains(t, string(content), expected)
+			}
+
+			for _, notExpected := range tt.notExpected {
+				assert.NotContains(t, string(content), notExpected)
+			}
+		})
+	}
+}
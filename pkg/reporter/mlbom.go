@@ -0,0 +1,180 @@
+package reporter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// aiTags are the signature tags identifying SDKs of AI models and frameworks
+var aiTags = []string{"ai", "ml", "llm", "llms"}
+
+// modelBomRefRegexp matches the characters replaced in the bom-ref of a model
+var modelBomRefRegexp = regexp.MustCompile(`[^a-z0-9.]+`)
+
+// modelTasks derive the task of a model from the signature tags, the first
+// task with a tag of the signature is used. Text generation is only derived from
+// the llm tags, since frameworks tag their agents, chains and loaders as text or chat.
+var modelTasks = []struct {
+	task string
+	tags []string
+}{
+	{"feature-extraction", []string{"embeddings"}},
+	{"automatic-speech-recognition", []string{"transcription"}},
+	{"text-to-image", []string{"generation"}},
+	{"translation", []string{"translate"}},
+	{"image-classification", []string{"vision", "image-analysis"}},
+	{"text-generation", []string{"llm", "llms"}},
+}
+
+func isAISignature(signature *callgraphv1.Signature) bool {
+	return slices.ContainsFunc(signature.GetTags(), func(tag string) bool {
+		return slices.Contains(aiTags, tag)
+	})
+}
+
+// modelTask returns the task of the model used through the SDK matched by a
+// signature, declared in the metadata or derived from the tags
+func modelTask(signature *callgraphv1.Signature, metadata common.ModelMetadata) string {
+	if metadata.Task != "" {
+		return metadata.Task
+	}
+
+	for _, modelTask := range modelTasks {
+		if slices.ContainsFunc(modelTask.tags, func(tag string) bool {
+			return slices.Contains(signature.GetTags(), tag)
+		}) {
+			return modelTask.task
+		}
+	}
+
+	return ""
+}
+
+// modelBomRef is the bom-ref of the model used through the SDK matched by a
+// signature, distinct from the bom-ref of the SDK component. Models with a known
+// name are keyed on their provider and name, so that all the SDKs of a model
+// describe a single model.
+func modelBomRef(signatureId string, name, provider string) string {
+	if name == "" {
+		return "model:" + signatureId
+	}
+
+	key := modelBomRefRegexp.ReplaceAllString(strings.ToLower(name), "-")
+	if provider != "" {
+		key = modelBomRefRegexp.ReplaceAllString(strings.ToLower(provider), "-") + "/" + key
+	}
+
+	return "model:" + key
+}
+
+// cdxModelComponent describes the model used through the SDK matched by a signature.
+// Only SDKs of models with a known task describe a model, not those of frameworks
+// for prompts, storage or agents. The evidences of the model are on the component
+// with the given bom-ref.
+func cdxModelComponent(signature *callgraphv1.Signature, metadata common.ModelMetadata,
+	componentBomRef string, occurrences int) (cdx.Component, bool) {
+	if !isAISignature(signature) {
+		return cdx.Component{}, false
+	}
+
+	task := modelTask(signature, metadata)
+	if task == "" {
+		return cdx.Component{}, false
+	}
+
+	// The vendor of a service SDK provides the model, unlike the vendor
+	// of a framework which integrates models of other providers
+	provider := metadata.Provider
+	if provider == "" && isServiceSignature(signature) {
+		provider = signature.GetVendor()
+	}
+
+	component := cdx.Component{
+		BOMRef:      modelBomRef(signature.GetId(), metadata.Name, provider),
+		Type:        cdx.ComponentTypeMachineLearningModel,
+		Name:        signature.GetProduct() + " - " + signature.GetService() + " model",
+		Description: signature.GetDescription(),
+		ModelCard: &cdx.MLModelCard{
+			ModelParameters: &cdx.MLModelParameters{Task: task},
+		},
+		Properties: utils.PtrTo([]cdx.Property{
+			{Name: "evidence", Value: componentBomRef},
+			{Name: "occurrences", Value: fmt.Sprintf("%d", occurrences)},
+		}),
+	}
+
+	if metadata.Name != "" {
+		component.Name = metadata.Name
+	}
+
+	if provider != "" {
+		component.Supplier = &cdx.OrganizationalEntity{Name: provider}
+		component.Publisher = provider
+	}
+
+	return component, true
+}
+
+// addModelComponent adds a model to the BOM. A model already described by the
+// SDK of another signature is merged with it, so that the model is listed once
+// with the evidences and occurrences of all its SDKs. A model of an earlier
+// enrichment of the BOM is replaced.
+func (c *CycloneDXReporter) addModelComponent(model cdx.Component) {
+	if !slices.Contains(c.detectedComponents, model.BOMRef) {
+		upsertComponent(c.bom.Components, model)
+		c.detectedComponents = append(c.detectedComponents, model.BOMRef)
+		return
+	}
+
+	existing := findComponent(c.bom.Components, model.BOMRef)
+	if existing.Properties == nil {
+		existing.Properties = utils.PtrTo([]cdx.Property{})
+	}
+
+	existing.Properties = utils.PtrTo(mergeModelProperties(*existing.Properties, *model.Properties))
+}
+
+// mergeModelProperties merges the properties of a model described by multiple
+// signatures. Evidences and tagged properties are combined, occurrences are summed
+// and the model is suppressed only when suppressed for every signature.
+func mergeModelProperties(properties, other []cdx.Property) []cdx.Property {
+	suppressed := slices.Contains(properties, cdx.Property{Name: "suppressed", Value: "true"}) &&
+		slices.Contains(other, cdx.Property{Name: "suppressed", Value: "true"})
+
+	merged := []cdx.Property{}
+	for _, property := range slices.Concat(properties, other) {
+		switch property.Name {
+		case "occurrences":
+			index := slices.IndexFunc(merged, func(p cdx.Property) bool {
+				return p.Name == "occurrences"
+			})
+
+			if index < 0 {
+				merged = append(merged, property)
+				continue
+			}
+
+			total, _ := strconv.Atoi(merged[index].Value)
+			occurrences, _ := strconv.Atoi(property.Value)
+			merged[index].Value = strconv.Itoa(total + occurrences)
+		case "suppressed":
+			if suppressed && !slices.Contains(merged, property) {
+				merged = append(merged, property)
+			}
+		default:
+			if !slices.Contains(merged, property) {
+				merged = append(merged, property)
+			}
+		}
+	}
+
+	return merged
+}
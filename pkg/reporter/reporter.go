@@ -13,3 +13,30 @@ type Reporter interface {
 	// Inform reporting module to finalise (e.g. write report to file)
 	Finish() error
 }
+
+// StreamingReporter is a Reporter which also receives the match results of every
+// file as soon as the file is analysed, so that the report can be written
+// incrementally instead of buffering all findings in the reporter until the end.
+// The workflow still retains all findings for the other reporters, the summary,
+// policies and baselines, so streaming does not bound the memory use of a scan.
+type StreamingReporter interface {
+	Reporter
+
+	// RecordMatchResults is called with the active and suppressed match results of
+	// an analysed file, with suppressions and diff status applied. Files are recorded
+	// in no particular order, but never concurrently. RecordCodeAnalysisFindings is
+	// still called with all findings at the end.
+	RecordMatchResults(results []common.EnrichedSignatureMatchResult) error
+}
+
+// skipReasonDescription returns a human readable description of why a file was not analyzed
+func skipReasonDescription(reason common.SkipReason) string {
+	switch reason {
+	case common.SkipReasonFileSize:
+		return "file size exceeds the analysis budget"
+	case common.SkipReasonTimeout:
+		return "analysis exceeded the time budget"
+	default:
+		return string(reason)
+	}
+}
@@ -0,0 +1,70 @@
+package reporter
+
+import (
+	"fmt"
+	"slices"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+// serviceTags are the signature tags identifying external services
+var serviceTags = []string{"saas", "paas", "iaas"}
+
+func isServiceSignature(signature *callgraphv1.Signature) bool {
+	return slices.ContainsFunc(signature.GetTags(), func(tag string) bool {
+		return slices.Contains(serviceTags, tag)
+	})
+}
+
+// serviceBomRef is the bom-ref of the service described by a signature, distinct
+// from the bom-ref of the component used to access the service
+func serviceBomRef(signatureId string) string {
+	return "service:" + signatureId
+}
+
+// cdxService describes the service used through the SDK matched by a signature.
+// The evidences of the service are on the component with the given bom-ref.
+func cdxService(signature *callgraphv1.Signature, metadata common.ServiceMetadata,
+	componentBomRef string, occurrences int) cdx.Service {
+	service := cdx.Service{
+		BOMRef:      serviceBomRef(signature.GetId()),
+		Name:        signature.GetProduct() + " - " + signature.GetService(),
+		Description: signature.GetDescription(),
+
+		// Services matched by signatures are third party unless declared otherwise
+		CrossesTrustBoundary: utils.PtrTo(true),
+		Properties: utils.PtrTo([]cdx.Property{
+			{Name: "evidence", Value: componentBomRef},
+			{Name: "occurrences", Value: fmt.Sprintf("%d", occurrences)},
+		}),
+	}
+
+	if signature.GetVendor() != "" {
+		service.Provider = &cdx.OrganizationalEntity{Name: signature.GetVendor()}
+	}
+
+	if len(metadata.Endpoints) > 0 {
+		service.Endpoints = utils.PtrTo(slices.Clone(metadata.Endpoints))
+	}
+
+	if metadata.TrustBoundary != nil {
+		service.CrossesTrustBoundary = utils.PtrTo(*metadata.TrustBoundary)
+	}
+
+	if len(metadata.DataFlows) > 0 {
+		data := make([]cdx.DataClassification, len(metadata.DataFlows))
+		for i, dataFlow := range metadata.DataFlows {
+			data[i] = cdx.DataClassification{
+				Flow:           cdx.DataFlow(dataFlow.Flow),
+				Classification: dataFlow.Classification,
+			}
+		}
+
+		service.Data = &data
+	}
+
+	return service
+}
@@ -0,0 +1,411 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifSourceRootBaseID is the base ID used for artifact locations relative
+	// to the scanned source directory
+	sarifSourceRootBaseID = "%SRCROOT%"
+)
+
+type SARIFReporterConfig struct {
+	Tool common.ToolMetadata
+
+	// Path defines the output file path
+	Path string
+
+	// SourcePath is the scanned directory. Artifact locations are
+	// reported relative to this path when possible.
+	SourcePath string
+
+	// NoSnippets omits the snippets of result regions
+	NoSnippets bool
+}
+
+// Minimal subset of the SARIF 2.1.0 object model required by xbom
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool                        `json:"tool"`
+	OriginalURIBaseID map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Invocations       []sarifInvocation                `json:"invocations,omitempty"`
+	Results           []sarifResult                    `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifToolComponent `json:"driver"`
+}
+
+type sarifToolComponent struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Organization   string      `json:"organization,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string               `json:"id"`
+	Name                 string               `json:"name,omitempty"`
+	ShortDescription     sarifMessage         `json:"shortDescription"`
+	FullDescription      sarifMessage         `json:"fullDescription"`
+	DefaultConfiguration sarifReportingConfig `json:"defaultConfiguration"`
+	Properties           sarifPropertyBag     `json:"properties,omitempty"`
+}
+
+type sarifReportingConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifPropertyBag map[string]any
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID        string          `json:"ruleId"`
+	RuleIndex     int             `json:"ruleIndex"`
+	Level         string          `json:"level"`
+	Message       sarifMessage    `json:"message"`
+	Locations     []sarifLocation `json:"locations"`
+	BaselineState string          `json:"baselineState,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndLine     int           `json:"endLine,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+type SARIFReporter struct {
+	config        SARIFReporterConfig
+	signatures    map[string]*callgraphv1.Signature
+	results       []sarifResult
+	notifications []sarifNotification
+}
+
+var _ Reporter = (*SARIFReporter)(nil)
+
+func NewSARIFReporter(config SARIFReporterConfig) (*SARIFReporter, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("SARIF report path is required")
+	}
+
+	return &SARIFReporter{
+		config:     config,
+		signatures: make(map[string]*callgraphv1.Signature),
+		results:    []sarifResult{},
+	}, nil
+}
+
+func (r *SARIFReporter) Name() string {
+	return "sarif"
+}
+
+func (r *SARIFReporter) RecordCodeAnalysisFindings(findings *common.CodeAnalysisFindings) error {
+	for signatureId, signatureMatchResults := range findings.SignatureWiseMatchResults {
+		for _, signatureMatchResult := range signatureMatchResults {
+			r.signatures[signatureId] = signatureMatchResult.MatchedSignature
+
+			artifactLocation := r.artifactLocation(signatureMatchResult.FilePath)
+
+			for i, condition := range signatureMatchResult.MatchedConditions {
+				for j := range condition.Evidences {
+					evidenceMetadata := signatureMatchResult.Metadata(i, j)
+
+					physicalLocation := sarifPhysicalLocation{
+						ArtifactLocation: artifactLocation,
+					}
+
+					if evidenceMetadata.CallerIdentifierMetadata != nil {
+						physicalLocation.Region = &sarifRegion{
+							StartLine:   int(evidenceMetadata.CallerIdentifierMetadata.StartLine) + 1,
+							StartColumn: int(evidenceMetadata.CallerIdentifierMetadata.StartColumn) + 1,
+							EndLine:     int(evidenceMetadata.CallerIdentifierMetadata.EndLine) + 1,
+							EndColumn:   int(evidenceMetadata.CallerIdentifierMetadata.EndColumn) + 1,
+						}
+
+						if !r.config.NoSnippets && strings.TrimSpace(evidenceMetadata.CallerIdentifierContent) != "" {
+							physicalLocation.Region.Snippet = &sarifMessage{
+								Text: evidenceMetadata.CallerIdentifierContent,
+							}
+						}
+					}
+
+					conditionType, conditionValue := "", ""
+					if condition.Condition != nil {
+						conditionType = condition.Condition.Type
+						conditionValue = strings.ReplaceAll(condition.Condition.Value, "\n", " ")
+					}
+
+					r.results = append(r.results, sarifResult{
+						RuleID: signatureId,
+						Level:  "note",
+						Message: sarifMessage{
+							Text: fmt.Sprintf("%s detected by %s condition: %s",
+								signatureDisplayName(signatureMatchResult.MatchedSignature), conditionType, conditionValue),
+						},
+						Locations: []sarifLocation{
+							{PhysicalLocation: physicalLocation},
+						},
+						BaselineState: sarifBaselineState(signatureMatchResult.DiffStatus),
+					})
+				}
+			}
+		}
+	}
+
+	// Files not analyzed are reported as tool execution notifications
+	// so that the results are known to be incomplete
+	for _, skippedFile := range findings.SkippedFiles {
+		r.notifications = append(r.notifications, sarifNotification{
+			Level: "warning",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("File not analyzed: %s", skipReasonDescription(skippedFile.Reason)),
+			},
+			Locations: []sarifLocation{
+				{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: r.artifactLocation(skippedFile.Path)}},
+			},
+		})
+	}
+
+	for _, failedFile := range findings.FailedFiles {
+		r.notifications = append(r.notifications, sarifNotification{
+			Level: "error",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("File analysis failed: %s", failedFile.Err.Error()),
+			},
+			Locations: []sarifLocation{
+				{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: r.artifactLocation(failedFile.Path)}},
+			},
+		})
+	}
+
+	return nil
+}
+
+func (r *SARIFReporter) Finish() error {
+	sarifReport := r.buildLog()
+
+	log.Infof("Writing SARIF report to %s", r.config.Path)
+
+	fd, err := os.Create(r.config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create SARIF report file: %w", err)
+	}
+
+	defer func() {
+		if err := fd.Close(); err != nil {
+			log.Errorf("Failed to close file %s: %v", r.config.Path, err)
+		}
+	}()
+
+	encoder := json.NewEncoder(fd)
+	encoder.SetIndent("", "  ")
+
+	err = encoder.Encode(sarifReport)
+	if err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+
+	fmt.Printf("📄 SARIF report saved at %s\n", r.config.Path)
+
+	return nil
+}
+
+// buildLog creates the SARIF log with rules and results sorted for
+// deterministic output
+func (r *SARIFReporter) buildLog() sarifLog {
+	signatureIds := make([]string, 0, len(r.signatures))
+	for signatureId := range r.signatures {
+		signatureIds = append(signatureIds, signatureId)
+	}
+	sort.Strings(signatureIds)
+
+	rules := make([]sarifRule, len(signatureIds))
+	ruleIndex := make(map[string]int, len(signatureIds))
+	for i, signatureId := range signatureIds {
+		rules[i] = sarifRuleFromSignature(r.signatures[signatureId])
+		ruleIndex[signatureId] = i
+	}
+
+	results := make([]sarifResult, len(r.results))
+	copy(results, r.results)
+	for i := range results {
+		results[i].RuleIndex = ruleIndex[results[i].RuleID]
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return sarifResultSortKey(results[i]) < sarifResultSortKey(results[j])
+	})
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifToolComponent{
+				Name:           r.config.Tool.Name,
+				Version:        r.config.Tool.Version,
+				InformationURI: r.config.Tool.InformationURI,
+				Organization:   r.config.Tool.VendorName,
+				Rules:          rules,
+			},
+		},
+		Results: results,
+	}
+
+	if len(r.notifications) > 0 {
+		notifications := make([]sarifNotification, len(r.notifications))
+		copy(notifications, r.notifications)
+
+		sort.SliceStable(notifications, func(i, j int) bool {
+			return notifications[i].Locations[0].PhysicalLocation.ArtifactLocation.URI <
+				notifications[j].Locations[0].PhysicalLocation.ArtifactLocation.URI
+		})
+
+		// The results are incomplete when the analysis of any file failed
+		executionSuccessful := !slices.ContainsFunc(notifications, func(notification sarifNotification) bool {
+			return notification.Level == "error"
+		})
+
+		run.Invocations = []sarifInvocation{
+			{
+				ExecutionSuccessful:        executionSuccessful,
+				ToolExecutionNotifications: notifications,
+			},
+		}
+	}
+
+	if r.config.SourcePath != "" {
+		if absSourcePath, err := filepath.Abs(r.config.SourcePath); err == nil {
+			run.OriginalURIBaseID = map[string]sarifArtifactLocation{
+				sarifSourceRootBaseID: {URI: "file://" + filepath.ToSlash(absSourcePath) + "/"},
+			}
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// artifactLocation returns the location of a file relative to the
+// scanned source directory, falling back to the path as is
+func (r *SARIFReporter) artifactLocation(filePath string) sarifArtifactLocation {
+	if relPath, ok := common.RelativeSourcePath(r.config.SourcePath, filePath); ok {
+		return sarifArtifactLocation{
+			URI:       relPath,
+			URIBaseID: sarifSourceRootBaseID,
+		}
+	}
+
+	return sarifArtifactLocation{URI: filepath.ToSlash(filePath)}
+}
+
+func sarifRuleFromSignature(signature *callgraphv1.Signature) sarifRule {
+	description := signature.GetDescription()
+	if description == "" {
+		description = signatureDisplayName(signature)
+	}
+
+	rule := sarifRule{
+		ID:               signature.GetId(),
+		Name:             signatureDisplayName(signature),
+		ShortDescription: sarifMessage{Text: description},
+		FullDescription: sarifMessage{
+			Text: fmt.Sprintf("%s (vendor: %s, product: %s, service: %s)",
+				description, signature.GetVendor(), signature.GetProduct(), signature.GetService()),
+		},
+		DefaultConfiguration: sarifReportingConfig{Level: "note"},
+	}
+
+	if len(signature.GetTags()) > 0 {
+		rule.Properties = sarifPropertyBag{
+			"tags": signature.GetTags(),
+		}
+	}
+
+	return rule
+}
+
+func sarifResultSortKey(result sarifResult) string {
+	location := result.Locations[0].PhysicalLocation
+
+	line, column := 0, 0
+	if location.Region != nil {
+		line, column = location.Region.StartLine, location.Region.StartColumn
+	}
+
+	return fmt.Sprintf("%s|%s|%010d|%010d|%s", result.RuleID, location.ArtifactLocation.URI,
+		line, column, result.Message.Text)
+}
+
+// sarifBaselineState maps the diff status of differential scans
+// to the SARIF baseline state of a result
+func sarifBaselineState(status common.DiffStatus) string {
+	switch status {
+	case common.DiffStatusIntroduced:
+		return "new"
+	case common.DiffStatusPreExisting:
+		return "unchanged"
+	default:
+		return ""
+	}
+}
+
+// signatureDisplayName returns a human readable name of a signature
+func signatureDisplayName(signature *callgraphv1.Signature) string {
+	if signature.GetProduct() == "" && signature.GetService() == "" {
+		return signature.GetId()
+	}
+
+	return signature.GetProduct() + " - " + signature.GetService()
+}
@@ -0,0 +1,191 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/code/core"
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSARIFReporter(t *testing.T) {
+	_, err := NewSARIFReporter(SARIFReporterConfig{})
+	assert.Error(t, err, "path is required")
+
+	reporter, err := NewSARIFReporter(SARIFReporterConfig{Path: "report.sarif"})
+	require.NoError(t, err)
+	assert.Equal(t, "sarif", reporter.Name())
+}
+
+func TestSARIFReporter_Finish(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputPath := filepath.Join(tmpDir, "report.sarif")
+
+	reporter, err := NewSARIFReporter(SARIFReporterConfig{
+		Tool: common.ToolMetadata{
+			Name:           "xbom",
+			Version:        "1.0.0",
+			InformationURI: "https://github.com/safedep/xbom",
+			VendorName:     "SafeDep",
+		},
+		Path:       outputPath,
+		SourcePath: "/src",
+	})
+	require.NoError(t, err)
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{
+			"openai.client": {
+				{
+					SignatureMatchResult: callgraph.SignatureMatchResult{
+						FilePath: "/src/app/main.py",
+						MatchedSignature: &callgraphv1.Signature{
+							Id:          "openai.client",
+							Description: "OpenAI client",
+							Vendor:      "OpenAI",
+							Product:     "OpenAI",
+							Service:     "AI client",
+							Tags:        []string{"ai", "llm"},
+						},
+						MatchedLanguageCode: core.LanguageCodePython,
+						MatchedConditions: []callgraph.MatchedCondition{
+							{
+								Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+									Type:  "call",
+									Value: "openai.*",
+								},
+								Evidences: []callgraph.MatchedEvidence{{}, {}},
+							},
+						},
+					},
+				},
+			},
+			"golang.crypto.hash": {
+				{
+					SignatureMatchResult: callgraph.SignatureMatchResult{
+						FilePath: "/src/hash.go",
+						MatchedSignature: &callgraphv1.Signature{
+							Id:          "golang.crypto.hash",
+							Description: "Hashing",
+						},
+						MatchedLanguageCode: core.LanguageCodeGo,
+						MatchedConditions: []callgraph.MatchedCondition{
+							{
+								Condition: &callgraphv1.Signature_LanguageMatcher_SignatureCondition{
+									Type:  "call",
+									Value: "crypto/sha256/Sum256",
+								},
+								Evidences: []callgraph.MatchedEvidence{{}},
+							},
+						},
+					},
+					DiffStatus: common.DiffStatusIntroduced,
+				},
+			},
+		},
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/web/bundle.min.js", Reason: common.SkipReasonFileSize},
+		},
+	}
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(content, &report))
+
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+
+	run := report.Runs[0]
+	assert.Equal(t, "xbom", run.Tool.Driver.Name)
+	assert.Equal(t, "1.0.0", run.Tool.Driver.Version)
+	assert.Equal(t, "SafeDep", run.Tool.Driver.Organization)
+
+	// Rules are sorted by signature ID
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "golang.crypto.hash", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "openai.client", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, "OpenAI client", run.Tool.Driver.Rules[1].ShortDescription.Text)
+	assert.ElementsMatch(t, []any{"ai", "llm"}, run.Tool.Driver.Rules[1].Properties["tags"])
+
+	// One result per evidence
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "golang.crypto.hash", run.Results[0].RuleID)
+	assert.Equal(t, 0, run.Results[0].RuleIndex)
+	assert.Equal(t, "hash.go", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "%SRCROOT%", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URIBaseID)
+	assert.Equal(t, "new", run.Results[0].BaselineState)
+
+	assert.Equal(t, "openai.client", run.Results[1].RuleID)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+	assert.Equal(t, "app/main.py", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+	assert.Empty(t, run.Results[1].BaselineState)
+
+	// Files not analyzed are reported as notifications
+	require.Len(t, run.Invocations, 1)
+	assert.True(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+
+	notification := run.Invocations[0].ToolExecutionNotifications[0]
+	assert.Equal(t, "warning", notification.Level)
+	assert.Equal(t, "web/bundle.min.js", notification.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestSARIFReporter_ArtifactLocationOutsideSource(t *testing.T) {
+	reporter, err := NewSARIFReporter(SARIFReporterConfig{Path: "report.sarif", SourcePath: "/src"})
+	require.NoError(t, err)
+
+	location := reporter.artifactLocation("/other/main.go")
+	assert.Equal(t, "/other/main.go", location.URI)
+	assert.Empty(t, location.URIBaseID)
+}
+
+func TestSARIFReporter_FailedFiles(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.sarif")
+
+	reporter, err := NewSARIFReporter(SARIFReporterConfig{Path: outputPath, SourcePath: "/src"})
+	require.NoError(t, err)
+
+	findings := &common.CodeAnalysisFindings{
+		SignatureWiseMatchResults: map[string][]common.EnrichedSignatureMatchResult{},
+		SkippedFiles: []common.SkippedFile{
+			{Path: "/src/web/bundle.min.js", Reason: common.SkipReasonFileSize},
+		},
+		FailedFiles: []common.FailedFile{
+			{Path: "/src/app/broken.py", Err: errors.New("failed to parse source file")},
+		},
+	}
+
+	require.NoError(t, reporter.RecordCodeAnalysisFindings(findings))
+	require.NoError(t, reporter.Finish())
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(content, &report))
+
+	// Failed files make the execution unsuccessful
+	require.Len(t, report.Runs[0].Invocations, 1)
+
+	invocation := report.Runs[0].Invocations[0]
+	assert.False(t, invocation.ExecutionSuccessful)
+	require.Len(t, invocation.ToolExecutionNotifications, 2)
+
+	notification := invocation.ToolExecutionNotifications[0]
+	assert.Equal(t, "error", notification.Level)
+	assert.Equal(t, "File analysis failed: failed to parse source file", notification.Message.Text)
+	assert.Equal(t, "app/broken.py", notification.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
@@ -21,8 +21,22 @@ type snippetData struct {
 	WasTruncated bool
 }
 
+// validateSnippetOptions checks that the snippet size limits are not negative
+func validateSnippetOptions(beforeLines, afterLines, maxBytes, maxLineChars int) error {
+	if beforeLines < 0 || afterLines < 0 {
+		return fmt.Errorf("snippet context lines must not be negative")
+	}
+
+	if maxBytes < 0 || maxLineChars < 0 {
+		return fmt.Errorf("snippet size limits must not be negative")
+	}
+
+	return nil
+}
+
 // extractFileSnippet reads a file and extracts a code snippet with context around the match.
 // It handles size limits to prevent excessive memory usage with minified or obfuscated code.
+// Size limits of zero mean no limit.
 func extractFileSnippet(filePath string,
 	startLine, endLine, beforeLines, afterLines, maxBytes, maxLineChars int,
 ) (snippetData, error) {
@@ -58,14 +72,14 @@ func extractFileSnippet(filePath string,
 
 		// Truncate line if too long
 		isTruncated := false
-		if len(line) > maxLineChars {
+		if maxLineChars > 0 && len(line) > maxLineChars {
 			line = line[:maxLineChars] + "... (truncated)"
 			isTruncated = true
 		}
 
 		// Check if adding this line would exceed max bytes
 		lineBytes := len(line) + 1 // +1 for newline
-		if totalBytes+lineBytes > maxBytes {
+		if maxBytes > 0 && totalBytes+lineBytes > maxBytes {
 			result.WasTruncated = true
 			break
 		}
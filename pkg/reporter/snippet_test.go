@@ -116,6 +116,24 @@ func main() {
 				assert.Less(t, len(snippet.Lines), 13)
 			},
 		},
+		{
+			name:         "zero size limits mean no limit",
+			filePath:     longLineFile,
+			startLine:    2,
+			endLine:      2,
+			beforeLines:  0,
+			afterLines:   0,
+			maxBytes:     0,
+			maxLineChars: 0,
+			wantErr:      false,
+			validate: func(t *testing.T, snippet snippetData, err error) {
+				assert.NoError(t, err)
+				assert.False(t, snippet.WasTruncated)
+				require.Len(t, snippet.Lines, 1)
+				assert.False(t, snippet.Lines[0].IsTruncated)
+				assert.Len(t, snippet.Lines[0].Content, 603)
+			},
+		},
 		{
 			name:         "handle file boundaries",
 			filePath:     testFile,
@@ -14,6 +14,14 @@ import (
 	"github.com/safedep/xbom/pkg/common"
 )
 
+// Grouping modes of the summary table
+const (
+	SummaryGroupByNone      = ""
+	SummaryGroupBySignature = "signature"
+	SummaryGroupByLanguage  = "language"
+	SummaryGroupByFile      = "file"
+)
+
 type SummaryReporterConfig struct {
 	// MaxResults limits the number of results to display (default: 50, 0 = unlimited)
 	MaxResults int
@@ -23,64 +31,117 @@ type SummaryReporterConfig struct {
 	GroupBy string
 	// Colorize enables colored output (default: true)
 	Colorize bool
+
+	// SourcePath is the scanned directory. File paths of file groups
+	// are shown relative to this path when possible.
+	SourcePath string
+
+	// NoDefaults uses the config as is, so that a zero MaxResults shows all
+	// results. The default limit replaces a zero MaxResults otherwise.
+	NoDefaults bool
 }
 
 type SummaryReporter struct {
 	config          SummaryReporterConfig
-	sigTable        table.Writer
+	rows            []summaryRow
 	findings        *common.CodeAnalysisFindings
 	totalFindings   int
+	suppressed      int
+	introduced      int
+	differential    bool
 	filesAffected   map[string]bool
 	languageCounts  map[string]int
 	signatureCounts map[string]int
 }
 
-var _ Reporter = (*SummaryReporter)(nil)
-
-func NewSummaryReporter(config SummaryReporterConfig) (*SummaryReporter, error) {
-	// Set defaults - only apply if not explicitly configured
-	if config.MaxResults == 0 {
-		config.MaxResults = 50
-	}
+// summaryRow is a finding shown in the summary table
+type summaryRow struct {
+	signature string
+	language  string
+	condition string
+	filePath  string
+	line      int // 1-based line of the evidence, zero when unknown
+	location  string
+}
 
-	sigTable := table.NewWriter()
-	sigTable.SetOutputMirror(os.Stdout)
-	sigTable.SetStyle(table.StyleRounded)
+// summaryGroup holds the rows of a group of the summary table
+type summaryGroup struct {
+	name string
+	rows []summaryRow
+}
 
-	sigTable.AppendHeader(table.Row{"#", "Signature", "Language", "Condition", "Evidence File", "Location"})
-	sigTable.SetTitle("🔍 Matched Signatures")
+// summaryColumn is a column of the summary table
+type summaryColumn struct {
+	header    string
+	groupBy   string // Grouping mode making the column redundant
+	widthMax  int
+	autoMerge bool
+	value     func(r *SummaryReporter, row summaryRow) string
+}
 
-	sigTable.SetColumnConfigs([]table.ColumnConfig{
-		{
-			Number:   0,
-			WidthMax: 5,
+var summaryColumns = []summaryColumn{
+	{
+		header:    "Signature",
+		groupBy:   SummaryGroupBySignature,
+		widthMax:  40,
+		autoMerge: true,
+		value: func(r *SummaryReporter, row summaryRow) string {
+			return r.colorize(color.New(color.FgCyan).SprintFunc(), row.signature)
 		},
-		{
-			Number:    1,
-			AutoMerge: true,
-			WidthMax:  40,
+	},
+	{
+		header:   "Language",
+		groupBy:  SummaryGroupByLanguage,
+		widthMax: 12,
+		value: func(r *SummaryReporter, row summaryRow) string {
+			return r.colorize(r.getLanguageColor(row.language), row.language)
 		},
-		{
-			Number:   2,
-			WidthMax: 12,
+	},
+	{
+		header:   "Condition",
+		widthMax: 35,
+		value: func(r *SummaryReporter, row summaryRow) string {
+			return row.condition
 		},
-		{
-			Number:   3,
-			WidthMax: 35,
+	},
+	{
+		header:   "Evidence File",
+		groupBy:  SummaryGroupByFile,
+		widthMax: 30,
+		value: func(r *SummaryReporter, row summaryRow) string {
+			// Base name for readability
+			return r.colorize(color.New(color.Faint).SprintFunc(), filepath.Base(row.filePath))
 		},
-		{
-			Number:   4,
-			WidthMax: 30,
-		},
-		{
-			Number:   5,
-			WidthMax: 20,
+	},
+	{
+		header:   "Location",
+		widthMax: 20,
+		value: func(r *SummaryReporter, row summaryRow) string {
+			return r.colorize(color.New(color.FgYellow).SprintFunc(), row.location)
 		},
-	})
+	},
+}
+
+var _ Reporter = (*SummaryReporter)(nil)
+
+func NewSummaryReporter(config SummaryReporterConfig) (*SummaryReporter, error) {
+	switch config.GroupBy {
+	case SummaryGroupByNone, SummaryGroupBySignature, SummaryGroupByLanguage, SummaryGroupByFile:
+	default:
+		return nil, fmt.Errorf("invalid summary grouping %q, expected %s, %s or %s", config.GroupBy,
+			SummaryGroupBySignature, SummaryGroupByLanguage, SummaryGroupByFile)
+	}
+
+	if config.MaxResults < 0 {
+		return nil, fmt.Errorf("summary limit must not be negative")
+	}
+
+	if config.MaxResults == 0 && !config.NoDefaults {
+		config.MaxResults = 50
+	}
 
 	return &SummaryReporter{
 		config:          config,
-		sigTable:        sigTable,
 		filesAffected:   make(map[string]bool),
 		languageCounts:  make(map[string]int),
 		signatureCounts: make(map[string]int),
@@ -122,11 +183,14 @@ func (r *SummaryReporter) getLanguageColor(language string) func(a ...interface{
 func (r *SummaryReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *common.CodeAnalysisFindings) error {
 	r.findings = codeAnalysisFindings
 
-	cyan := color.New(color.FgCyan).SprintFunc()
-	dim := color.New(color.Faint).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, signatureResults := range codeAnalysisFindings.SuppressedMatchResults {
+		for _, signatureMatchResult := range signatureResults {
+			for _, condition := range signatureMatchResult.MatchedConditions {
+				r.suppressed += len(condition.Evidences)
+			}
+		}
+	}
 
-	rowNum := 0
 	for _, signatureResults := range codeAnalysisFindings.SignatureWiseMatchResults {
 		for _, signatureMatchResult := range signatureResults {
 			// Collect statistics
@@ -134,20 +198,22 @@ func (r *SummaryReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *commo
 			r.languageCounts[string(signatureMatchResult.MatchedLanguageCode)]++
 			r.signatureCounts[signatureMatchResult.MatchedSignature.Id]++
 
-			for _, condition := range signatureMatchResult.MatchedConditions {
-				for _, evidence := range condition.Evidences {
+			for i, condition := range signatureMatchResult.MatchedConditions {
+				for j := range condition.Evidences {
 					r.totalFindings++
 
-					// Check if we've reached the limit
-					if r.config.MaxResults > 0 && rowNum >= r.config.MaxResults {
-						continue
+					if signatureMatchResult.DiffStatus != "" {
+						r.differential = true
+						if signatureMatchResult.DiffStatus == common.DiffStatusIntroduced {
+							r.introduced++
+						}
 					}
 
-					rowNum++
-
+					line := 0
 					evidenceDetailString := "Unknown"
-					evidenceMetadata := evidence.Metadata(signatureMatchResult.TreeData)
+					evidenceMetadata := signatureMatchResult.Metadata(i, j)
 					if evidenceMetadata.CallerIdentifierMetadata != nil {
+						line = int(evidenceMetadata.CallerIdentifierMetadata.StartLine) + 1
 						evidenceDetailString = fmt.Sprintf(
 							"L%d:%d-L%d:%d",
 							evidenceMetadata.CallerIdentifierMetadata.StartLine+1,
@@ -157,32 +223,14 @@ func (r *SummaryReporter) RecordCodeAnalysisFindings(codeAnalysisFindings *commo
 						)
 					}
 
-					// Format signature ID with color
-					sigId := r.colorize(cyan, signatureMatchResult.MatchedSignature.Id)
-
-					// Format language with appropriate color
-					langColor := r.getLanguageColor(string(signatureMatchResult.MatchedLanguageCode))
-					lang := r.colorize(langColor, string(signatureMatchResult.MatchedLanguageCode))
-
-					// Format condition
-					conditionStr := fmt.Sprintf("%s:\n%s", condition.Condition.Type, condition.Condition.Value)
-
-					// Format file path (base name for readability)
-					fileName := filepath.Base(signatureMatchResult.FilePath)
-					filePath := r.colorize(dim, fileName)
-
-					// Format location with color
-					location := r.colorize(yellow, evidenceDetailString)
-
-					r.sigTable.AppendRow(table.Row{
-						rowNum,
-						sigId,
-						lang,
-						conditionStr,
-						filePath,
-						location,
+					r.rows = append(r.rows, summaryRow{
+						signature: signatureMatchResult.MatchedSignature.Id,
+						language:  string(signatureMatchResult.MatchedLanguageCode),
+						condition: fmt.Sprintf("%s:\n%s", condition.Condition.Type, condition.Condition.Value),
+						filePath:  signatureMatchResult.FilePath,
+						line:      line,
+						location:  evidenceDetailString,
 					})
-					r.sigTable.AppendSeparator()
 				}
 			}
 		}
@@ -202,7 +250,7 @@ func (r *SummaryReporter) Finish() error {
 
 	// Render table if there are findings
 	if r.totalFindings > 0 {
-		r.sigTable.Render()
+		r.renderMatches()
 
 		// Show truncation message if needed
 		if r.config.MaxResults > 0 && r.totalFindings > r.config.MaxResults {
@@ -220,9 +268,185 @@ func (r *SummaryReporter) Finish() error {
 		ui.Println(r.colorize(green, "✓ No signature matches found!"))
 	}
 
+	r.renderSkippedFiles()
+	r.renderDiagnostics()
+
 	return nil
 }
 
+// renderMatches renders the findings up to the limit in a single table, or
+// in a table per group with the number of findings of the group
+func (r *SummaryReporter) renderMatches() {
+	columns := []summaryColumn{}
+	for _, column := range summaryColumns {
+		if column.groupBy == "" || column.groupBy != r.config.GroupBy {
+			columns = append(columns, column)
+		}
+	}
+
+	groups := groupSummaryRows(r.rows, r.config.GroupBy)
+	bold := color.New(color.Bold).SprintFunc()
+
+	rowNum := 0
+	for i, group := range groups {
+		if r.config.MaxResults > 0 && rowNum >= r.config.MaxResults {
+			break
+		}
+
+		title := "🔍 Matched Signatures"
+		if r.config.GroupBy != SummaryGroupByNone {
+			title = fmt.Sprintf("🔍 %s %s (%d findings)", strings.ToUpper(r.config.GroupBy[:1])+r.config.GroupBy[1:],
+				r.colorize(bold, r.groupName(group.name)), len(group.rows))
+		}
+
+		sigTable := table.NewWriter()
+		sigTable.SetOutputMirror(os.Stdout)
+		sigTable.SetStyle(table.StyleRounded)
+		sigTable.SetTitle(title)
+
+		header := table.Row{"#"}
+		columnConfigs := []table.ColumnConfig{{Number: 1, WidthMax: 5}}
+		for k, column := range columns {
+			header = append(header, column.header)
+			columnConfigs = append(columnConfigs, table.ColumnConfig{
+				Number:    k + 2,
+				AutoMerge: column.autoMerge,
+				WidthMax:  column.widthMax,
+			})
+		}
+
+		sigTable.AppendHeader(header)
+		sigTable.SetColumnConfigs(columnConfigs)
+
+		for _, row := range group.rows {
+			if r.config.MaxResults > 0 && rowNum >= r.config.MaxResults {
+				break
+			}
+
+			rowNum++
+
+			tableRow := table.Row{rowNum}
+			for _, column := range columns {
+				tableRow = append(tableRow, column.value(r, row))
+			}
+
+			sigTable.AppendRow(tableRow)
+			sigTable.AppendSeparator()
+		}
+
+		if i > 0 {
+			ui.Println()
+		}
+
+		sigTable.Render()
+	}
+}
+
+// groupName returns the name of a group to show, file paths are
+// relative to the source path when possible
+func (r *SummaryReporter) groupName(name string) string {
+	if r.config.GroupBy != SummaryGroupByFile {
+		return name
+	}
+
+	if relativePath, ok := common.RelativeSourcePath(r.config.SourcePath, name); ok {
+		return relativePath
+	}
+
+	return name
+}
+
+// groupSummaryRows groups the rows by the grouping mode. Groups are sorted by
+// their number of rows, largest first, then by name, and rows of a group by
+// file and line. Without grouping all rows are in a single group in their
+// original order.
+func groupSummaryRows(rows []summaryRow, groupBy string) []summaryGroup {
+	if groupBy == SummaryGroupByNone {
+		return []summaryGroup{{rows: rows}}
+	}
+
+	groupIndex := map[string]int{}
+	groups := []summaryGroup{}
+
+	for _, row := range rows {
+		var name string
+		switch groupBy {
+		case SummaryGroupBySignature:
+			name = row.signature
+		case SummaryGroupByLanguage:
+			name = row.language
+		case SummaryGroupByFile:
+			name = row.filePath
+		}
+
+		index, ok := groupIndex[name]
+		if !ok {
+			index = len(groups)
+			groupIndex[name] = index
+			groups = append(groups, summaryGroup{name: name})
+		}
+
+		groups[index].rows = append(groups[index].rows, row)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.rows, func(i, j int) bool {
+			if group.rows[i].filePath != group.rows[j].filePath {
+				return group.rows[i].filePath < group.rows[j].filePath
+			}
+
+			return group.rows[i].line < group.rows[j].line
+		})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].rows) != len(groups[j].rows) {
+			return len(groups[i].rows) > len(groups[j].rows)
+		}
+
+		return groups[i].name < groups[j].name
+	})
+
+	return groups
+}
+
+// renderSkippedFiles lists the files not analyzed, since findings
+// in them are missing from the results
+func (r *SummaryReporter) renderSkippedFiles() {
+	if r.findings == nil || len(r.findings.SkippedFiles) == 0 {
+		return
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	ui.Println()
+	ui.Println(r.colorize(yellow, fmt.Sprintf("⚠️  %d files not analyzed:", len(r.findings.SkippedFiles))))
+
+	for _, skippedFile := range r.findings.SkippedFiles {
+		ui.Println(fmt.Sprintf("  • %s %s", skippedFile.Path,
+			r.colorize(dim, fmt.Sprintf("(%s)", skipReasonDescription(skippedFile.Reason)))))
+	}
+}
+
+// renderDiagnostics lists the files whose analysis failed, since
+// findings in them are missing from the results
+func (r *SummaryReporter) renderDiagnostics() {
+	if r.findings == nil || len(r.findings.FailedFiles) == 0 {
+		return
+	}
+
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	ui.Println()
+	ui.Println(r.colorize(red, fmt.Sprintf("❗ Analysis of %d files failed, results are incomplete:", len(r.findings.FailedFiles))))
+
+	for _, failedFile := range r.findings.FailedFiles {
+		ui.Println(fmt.Sprintf("  • %s %s", failedFile.Path, r.colorize(dim, fmt.Sprintf("(%v)", failedFile.Err))))
+	}
+}
+
 func (r *SummaryReporter) renderStatistics() {
 	bold := color.New(color.Bold).SprintFunc()
 	cyan := color.New(color.FgCyan).SprintFunc()
@@ -240,6 +464,20 @@ func (r *SummaryReporter) renderStatistics() {
 		r.colorize(bold, fmt.Sprintf("%d", r.totalFindings)),
 	})
 
+	if r.differential {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Introduced Findings:"),
+			r.colorize(bold, fmt.Sprintf("%d", r.introduced)),
+		})
+	}
+
+	if r.suppressed > 0 {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Suppressed Findings:"),
+			r.colorize(bold, fmt.Sprintf("%d", r.suppressed)),
+		})
+	}
+
 	statsTable.AppendRow(table.Row{
 		r.colorize(cyan, "Unique Signatures:"),
 		r.colorize(bold, fmt.Sprintf("%d", len(r.signatureCounts))),
@@ -250,6 +488,34 @@ func (r *SummaryReporter) renderStatistics() {
 		r.colorize(bold, fmt.Sprintf("%d", len(r.filesAffected))),
 	})
 
+	if r.findings != nil && len(r.findings.SkippedFiles) > 0 {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Files Not Analyzed:"),
+			r.colorize(bold, fmt.Sprintf("%d", len(r.findings.SkippedFiles))),
+		})
+	}
+
+	if r.findings != nil && len(r.findings.FailedFiles) > 0 {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Files Failed:"),
+			r.colorize(bold, fmt.Sprintf("%d", len(r.findings.FailedFiles))),
+		})
+	}
+
+	if r.findings != nil && r.findings.Stats.CachedFiles > 0 {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Files From Cache:"),
+			r.colorize(bold, fmt.Sprintf("%d", r.findings.Stats.CachedFiles)),
+		})
+	}
+
+	if r.findings != nil && r.findings.Stats.ExcludedPaths > 0 {
+		statsTable.AppendRow(table.Row{
+			r.colorize(cyan, "Excluded Paths:"),
+			r.colorize(bold, fmt.Sprintf("%d", r.findings.Stats.ExcludedPaths)),
+		})
+	}
+
 	statsTable.AppendRow(table.Row{
 		r.colorize(cyan, "Languages Detected:"),
 		r.colorize(bold, fmt.Sprintf("%d", len(r.languageCounts))),
@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummaryReporter(t *testing.T) {
+	reporter, err := NewSummaryReporter(SummaryReporterConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "summary", reporter.Name())
+	assert.Equal(t, 50, reporter.config.MaxResults, "default limit")
+
+	reporter, err = NewSummaryReporter(SummaryReporterConfig{NoDefaults: true})
+	require.NoError(t, err)
+	assert.Zero(t, reporter.config.MaxResults, "zero is unlimited without defaults")
+
+	reporter, err = NewSummaryReporter(SummaryReporterConfig{MaxResults: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 5, reporter.config.MaxResults)
+
+	for _, groupBy := range []string{SummaryGroupBySignature, SummaryGroupByLanguage, SummaryGroupByFile} {
+		_, err := NewSummaryReporter(SummaryReporterConfig{GroupBy: groupBy})
+		assert.NoError(t, err, groupBy)
+	}
+
+	_, err = NewSummaryReporter(SummaryReporterConfig{GroupBy: "vendor"})
+	assert.Error(t, err)
+
+	_, err = NewSummaryReporter(SummaryReporterConfig{MaxResults: -1})
+	assert.Error(t, err)
+}
+
+func TestGroupSummaryRows(t *testing.T) {
+	rows := []summaryRow{
+		{signature: "openai.client", language: "python", filePath: "/src/app.py", line: 9},
+		{signature: "crypto.hash", language: "go", filePath: "/src/hash.go", line: 2},
+		{signature: "openai.client", language: "python", filePath: "/src/worker.py", line: 3},
+		{signature: "crypto.hash", language: "python", filePath: "/src/app.py", line: 4},
+		{signature: "aws.s3", language: "go", filePath: "/src/hash.go", line: 5},
+	}
+
+	groupNames := func(groups []summaryGroup) []string {
+		names := []string{}
+		for _, group := range groups {
+			names = append(names, group.name)
+		}
+
+		return names
+	}
+
+	t.Run("no grouping", func(t *testing.T) {
+		groups := groupSummaryRows(rows, SummaryGroupByNone)
+		require.Len(t, groups, 1)
+		assert.Equal(t, rows, groups[0].rows)
+	})
+
+	t.Run("by signature", func(t *testing.T) {
+		groups := groupSummaryRows(rows, SummaryGroupBySignature)
+		assert.Equal(t, []string{"crypto.hash", "openai.client", "aws.s3"}, groupNames(groups))
+		assert.Equal(t, []summaryRow{rows[3], rows[1]}, groups[0].rows)
+	})
+
+	t.Run("by language", func(t *testing.T) {
+		groups := groupSummaryRows(rows, SummaryGroupByLanguage)
+		assert.Equal(t, []string{"python", "go"}, groupNames(groups))
+		assert.Len(t, groups[0].rows, 3)
+		assert.Len(t, groups[1].rows, 2)
+	})
+
+	t.Run("by file", func(t *testing.T) {
+		groups := groupSummaryRows(rows, SummaryGroupByFile)
+		assert.Equal(t, []string{"/src/app.py", "/src/hash.go", "/src/worker.py"}, groupNames(groups))
+		assert.Equal(t, []summaryRow{rows[3], rows[0]}, groups[0].rows, "rows are sorted by line")
+	})
+}
+
+func TestSummaryReporterGroupName(t *testing.T) {
+	reporter, err := NewSummaryReporter(SummaryReporterConfig{GroupBy: SummaryGroupByFile, SourcePath: "/src"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "app/main.py", reporter.groupName("/src/app/main.py"))
+	assert.Equal(t, "/other/main.py", reporter.groupName("/other/main.py"))
+}
@@ -0,0 +1,147 @@
+package signatures
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SignatureFilter selects a subset of signatures. All non-empty criteria
+// must be satisfied for a signature to be selected. An empty filter selects
+// all signatures.
+type SignatureFilter struct {
+	// IncludeTags selects signatures having at least one of the tags
+	IncludeTags []string
+
+	// ExcludeTags drops signatures having any of the tags
+	ExcludeTags []string
+
+	// Vendors selects signatures from any of the vendors (case insensitive)
+	Vendors []string
+
+	// Languages selects signatures having a matcher for any of the languages.
+	// Matchers for other languages are removed from the selected signatures.
+	Languages []string
+
+	// IDPatterns selects signatures with ID matching any of the glob patterns
+	// eg. `langchain.*`
+	IDPatterns []string
+}
+
+// IsEmpty returns true when the filter does not have any criteria
+func (f SignatureFilter) IsEmpty() bool {
+	return len(f.IncludeTags) == 0 && len(f.ExcludeTags) == 0 &&
+		len(f.Vendors) == 0 && len(f.Languages) == 0 && len(f.IDPatterns) == 0
+}
+
+// FilterSignatures returns the signatures selected by the filter. Tags, vendors
+// and languages are compared case insensitively. The input signatures are not modified.
+func FilterSignatures(signatures []*callgraphv1.Signature, filter SignatureFilter) ([]*callgraphv1.Signature, error) {
+	filter = SignatureFilter{
+		IncludeTags: normalizeFilterValues(filter.IncludeTags),
+		ExcludeTags: normalizeFilterValues(filter.ExcludeTags),
+		Vendors:     normalizeFilterValues(filter.Vendors),
+		Languages:   normalizeFilterValues(filter.Languages),
+		IDPatterns:  filter.IDPatterns,
+	}
+
+	if filter.IsEmpty() {
+		return signatures, nil
+	}
+
+	for _, pattern := range filter.IDPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid signature ID pattern %q: %w", pattern, err)
+		}
+	}
+
+	filteredSignatures := []*callgraphv1.Signature{}
+	for _, signature := range signatures {
+		if !filter.matches(signature) {
+			continue
+		}
+
+		if len(filter.Languages) > 0 {
+			signature = selectSignatureLanguages(signature, filter.Languages)
+			if len(signature.GetLanguages()) == 0 {
+				continue
+			}
+		}
+
+		filteredSignatures = append(filteredSignatures, signature)
+	}
+
+	return filteredSignatures, nil
+}
+
+// matches expects the filter values to be normalized
+func (f SignatureFilter) matches(signature *callgraphv1.Signature) bool {
+	tags := normalizeFilterValues(signature.GetTags())
+
+	if len(f.IncludeTags) > 0 && !containsAny(tags, f.IncludeTags) {
+		return false
+	}
+
+	if len(f.ExcludeTags) > 0 && containsAny(tags, f.ExcludeTags) {
+		return false
+	}
+
+	if len(f.Vendors) > 0 && !slices.Contains(f.Vendors, strings.ToLower(signature.GetVendor())) {
+		return false
+	}
+
+	if len(f.IDPatterns) > 0 {
+		matched := false
+		for _, pattern := range f.IDPatterns {
+			if ok, _ := path.Match(pattern, signature.GetId()); ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectSignatureLanguages returns a copy of the signature with
+// language matchers limited to the given languages
+func selectSignatureLanguages(signature *callgraphv1.Signature, languages []string) *callgraphv1.Signature {
+	selected := proto.Clone(signature).(*callgraphv1.Signature)
+	for language := range selected.Languages {
+		if !slices.Contains(languages, strings.ToLower(language)) {
+			delete(selected.Languages, language)
+		}
+	}
+
+	return selected
+}
+
+func normalizeFilterValues(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+
+	return normalized
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if slices.Contains(values, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
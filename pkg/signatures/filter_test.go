@@ -0,0 +1,126 @@
+package signatures
+
+import (
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFilterSignatures() []*callgraphv1.Signature {
+	return []*callgraphv1.Signature{
+		{
+			Id:     "openai.client",
+			Vendor: "OpenAI",
+			Tags:   []string{"ai", "llm"},
+			Languages: map[string]*callgraphv1.Signature_LanguageMatcher{
+				"python": {Match: "any"},
+				"java":   {Match: "any"},
+			},
+		},
+		{
+			Id:     "langchain.core.prompts",
+			Vendor: "LangChain",
+			Tags:   []string{"ai", "langchain"},
+			Languages: map[string]*callgraphv1.Signature_LanguageMatcher{
+				"python": {Match: "any"},
+			},
+		},
+		{
+			Id:     "golang.crypto.hash",
+			Vendor: "Go",
+			Tags:   []string{"capability", "crypto", "hash"},
+			Languages: map[string]*callgraphv1.Signature_LanguageMatcher{
+				"go": {Match: "any"},
+			},
+		},
+	}
+}
+
+func TestFilterSignatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      SignatureFilter
+		expectedIDs []string
+		expectErr   bool
+	}{
+		{
+			name:        "empty filter selects all",
+			filter:      SignatureFilter{},
+			expectedIDs: []string{"openai.client", "langchain.core.prompts", "golang.crypto.hash"},
+		},
+		{
+			name:        "include tags",
+			filter:      SignatureFilter{IncludeTags: []string{"llm", "hash"}},
+			expectedIDs: []string{"openai.client", "golang.crypto.hash"},
+		},
+		{
+			name:        "exclude tags",
+			filter:      SignatureFilter{ExcludeTags: []string{"capability"}},
+			expectedIDs: []string{"openai.client", "langchain.core.prompts"},
+		},
+		{
+			name:        "vendor is case insensitive",
+			filter:      SignatureFilter{Vendors: []string{"openai"}},
+			expectedIDs: []string{"openai.client"},
+		},
+		{
+			name:        "language",
+			filter:      SignatureFilter{Languages: []string{"Go"}},
+			expectedIDs: []string{"golang.crypto.hash"},
+		},
+		{
+			name:        "id glob",
+			filter:      SignatureFilter{IDPatterns: []string{"langchain.*"}},
+			expectedIDs: []string{"langchain.core.prompts"},
+		},
+		{
+			name: "criteria are combined",
+			filter: SignatureFilter{
+				IncludeTags: []string{"ai"},
+				Languages:   []string{"python"},
+				IDPatterns:  []string{"openai.*"},
+			},
+			expectedIDs: []string{"openai.client"},
+		},
+		{
+			name:      "invalid glob",
+			filter:    SignatureFilter{IDPatterns: []string{"[openai"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterSignatures(testFilterSignatures(), tt.filter)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, sig := range filtered {
+				ids = append(ids, sig.Id)
+			}
+
+			assert.ElementsMatch(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestFilterSignaturesLanguageSelection(t *testing.T) {
+	signatures := testFilterSignatures()
+
+	filtered, err := FilterSignatures(signatures, SignatureFilter{Languages: []string{"python"}})
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+
+	assert.Len(t, filtered[0].Languages, 1)
+	assert.Contains(t, filtered[0].Languages, "python")
+
+	// Input signatures must not be modified
+	assert.Len(t, signatures[0].Languages, 2)
+}
@@ -4,12 +4,16 @@ import (
 	"embed"
 	"fmt"
 	"io/fs"
+	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
 	"github.com/safedep/code/plugin/callgraph"
 	"github.com/safedep/dry/log"
+	"github.com/safedep/xbom/pkg/common"
 	"gopkg.in/yaml.v3"
 )
 
@@ -32,6 +36,42 @@ type signatureFile struct {
 	Signatures []callgraphv1.Signature `yaml:"signatures"`
 }
 
+// signatureExtensionFile holds the optional fields of signature files
+// which are not part of the signature schema
+type signatureExtensionFile struct {
+	Signatures []struct {
+		common.ServiceMetadata `yaml:",inline"`
+		Model                  common.ModelMetadata `yaml:"model"`
+	} `yaml:"signatures"`
+}
+
+// SignatureMetadata holds the optional metadata declared by signatures, keyed
+// by signature ID. Only signatures declaring the metadata are included.
+type SignatureMetadata struct {
+	Services map[string]common.ServiceMetadata
+	Models   map[string]common.ModelMetadata
+}
+
+// dataFlows are the valid flows of service data
+var dataFlows = []string{"inbound", "outbound", "bi-directional", "unknown"}
+
+// signatureSource is a file system containing signature files following
+// the `$vendor/$product/$service.yaml` layout. The name is used to report
+// the origin of a signature file in errors.
+type signatureSource struct {
+	name  string
+	files fs.FS
+}
+
+// loadedSignature keeps track of the file a signature was loaded from
+// so that errors such as duplicates can point to the offending file.
+type loadedSignature struct {
+	signature *callgraphv1.Signature
+	service   common.ServiceMetadata
+	model     common.ModelMetadata
+	file      string
+}
+
 // LoadSignatures loads the signatures from the specified vendor, product, and service.
 // It returns a slice of callgraph.Signature and an error if any occurs during the loading process.
 //
@@ -39,6 +79,107 @@ type signatureFile struct {
 // If a product is not specified, it will load all signatures for the given vendor.
 // If a vendor is not specified, it will load all the signatures
 func LoadSignatures(vendor string, product string, service string) ([]*callgraphv1.Signature, error) {
+	loadedSignatures, err := loadSignatures([]signatureSource{embeddedSignatureSource()}, vendor, product, service)
+	if err != nil {
+		return []*callgraphv1.Signature{}, err
+	}
+
+	return signaturesOf(loadedSignatures), nil
+}
+
+// LoadAllSignatures is a wrapper to get all signatures conveniently
+func LoadAllSignatures() ([]*callgraphv1.Signature, error) {
+	return LoadSignatures("", "", "")
+}
+
+// LoadAllSignaturesWithDirs loads all the embedded signatures along with the signatures
+// found in the given local directories. The directories must follow the same
+// `$vendor/$product/$service.yaml` layout as the embedded signatures. Signatures from
+// all sources are validated together and must have unique IDs.
+func LoadAllSignaturesWithDirs(dirs []string) ([]*callgraphv1.Signature, error) {
+	sources, err := signatureSources(dirs)
+	if err != nil {
+		return []*callgraphv1.Signature{}, err
+	}
+
+	loadedSignatures, err := loadSignatures(sources, "", "", "")
+	if err != nil {
+		return []*callgraphv1.Signature{}, err
+	}
+
+	return signaturesOf(loadedSignatures), nil
+}
+
+// LoadAllSignaturesWithMetadata loads the signatures like LoadAllSignaturesWithDirs
+// along with the metadata of the services and models they describe
+func LoadAllSignaturesWithMetadata(dirs []string) ([]*callgraphv1.Signature, SignatureMetadata, error) {
+	metadata := SignatureMetadata{
+		Services: map[string]common.ServiceMetadata{},
+		Models:   map[string]common.ModelMetadata{},
+	}
+
+	sources, err := signatureSources(dirs)
+	if err != nil {
+		return []*callgraphv1.Signature{}, metadata, err
+	}
+
+	loadedSignatures, err := loadSignatures(sources, "", "", "")
+	if err != nil {
+		return []*callgraphv1.Signature{}, metadata, err
+	}
+
+	for _, loaded := range loadedSignatures {
+		if !loaded.service.IsEmpty() {
+			metadata.Services[loaded.signature.Id] = loaded.service
+		}
+
+		if !loaded.model.IsEmpty() {
+			metadata.Models[loaded.signature.Id] = loaded.model
+		}
+	}
+
+	return signaturesOf(loadedSignatures), metadata, nil
+}
+
+// signatureSources returns the embedded signatures and the given directories as sources
+func signatureSources(dirs []string) ([]signatureSource, error) {
+	sources := []signatureSource{embeddedSignatureSource()}
+	for _, dir := range dirs {
+		st, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access signatures directory %s: %w", dir, err)
+		}
+
+		if !st.IsDir() {
+			return nil, fmt.Errorf("signatures path %s is not a directory", dir)
+		}
+
+		sources = append(sources, signatureSource{
+			name:  dir,
+			files: os.DirFS(dir),
+		})
+	}
+
+	return sources, nil
+}
+
+func signaturesOf(loadedSignatures []loadedSignature) []*callgraphv1.Signature {
+	signatures := make([]*callgraphv1.Signature, len(loadedSignatures))
+	for i, loaded := range loadedSignatures {
+		signatures[i] = loaded.signature
+	}
+
+	return signatures
+}
+
+func embeddedSignatureSource() signatureSource {
+	return signatureSource{
+		name:  "embedded",
+		files: signatureFiles,
+	}
+}
+
+func loadSignatures(sources []signatureSource, vendor, product, service string) ([]loadedSignature, error) {
 	isSingleSignatureFile := false
 	subDirs := []string{".", vendor}
 	if product != "" {
@@ -53,14 +194,45 @@ func LoadSignatures(vendor string, product string, service string) ([]*callgraph
 
 	log.Debugf("Reading signatures from: %s (%t)", signaturesPath, isSingleSignatureFile)
 
+	loadedSignatures := []loadedSignature{}
+	for _, source := range sources {
+		signatures, err := loadSignaturesFromSource(source, signaturesPath, isSingleSignatureFile)
+		if err != nil {
+			return nil, err
+		}
+
+		loadedSignatures = append(loadedSignatures, signatures...)
+	}
+
+	// Validate the loaded signatures
+	validationErr := callgraph.ValidateSignatures(signaturesOf(loadedSignatures))
+	if validationErr != nil {
+		return nil, fmt.Errorf("invalid signatures: %w", validationErr)
+	}
+
+	validationErr = validateServiceMetadata(loadedSignatures)
+	if validationErr != nil {
+		return nil, fmt.Errorf("invalid signatures: %w", validationErr)
+	}
+
+	// Ensure no duplicate signatures
+	duplicationErr := checkDuplicateSignatures(loadedSignatures)
+	if duplicationErr != nil {
+		return nil, fmt.Errorf("duplicate signatures found: %w", duplicationErr)
+	}
+
+	return loadedSignatures, nil
+}
+
+func loadSignaturesFromSource(source signatureSource, signaturesPath string, isSingleSignatureFile bool) ([]loadedSignature, error) {
 	if isSingleSignatureFile {
-		return loadSignatureFile(signaturesPath)
+		return loadSignatureFile(source, signaturesPath)
 	}
 
 	// Walk through shortlisted files and parse signatures
-	targetSignatures := []*callgraphv1.Signature{}
+	targetSignatures := []loadedSignature{}
 
-	err := fs.WalkDir(signatureFiles, signaturesPath, func(path string, d fs.DirEntry, err error) error {
+	err := fs.WalkDir(source.files, signaturesPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil || d.IsDir() {
 			return nil
 		}
@@ -70,68 +242,95 @@ func LoadSignatures(vendor string, product string, service string) ([]*callgraph
 			return nil
 		}
 
-		signatures, err := loadSignatureFile(path)
+		signatures, err := loadSignatureFile(source, path)
 		if err != nil {
-			return fmt.Errorf("failed to load signature file %s: %v", path, err)
+			return fmt.Errorf("failed to load signature file %s: %v", sourceFilePath(source, path), err)
 		}
 
 		targetSignatures = append(targetSignatures, signatures...)
 		return nil
 	})
 	if err != nil {
-		return []*callgraphv1.Signature{}, fmt.Errorf("failed to walk through signature files: %w", err)
-	}
-
-	// Validate the loaded signatures
-	validationErr := callgraph.ValidateSignatures(targetSignatures)
-	if validationErr != nil {
-		return []*callgraphv1.Signature{}, fmt.Errorf("invalid signatures: %w", validationErr)
-	}
-
-	// Ensure no duplicate signatures
-	duplicationErr := checkDuplicateSignatures(targetSignatures)
-	if duplicationErr != nil {
-		return []*callgraphv1.Signature{}, fmt.Errorf("duplicate signatures found: %w", duplicationErr)
+		return []loadedSignature{}, fmt.Errorf("failed to walk through signature files: %w", err)
 	}
 
 	return targetSignatures, nil
 }
 
-// LoadAllSignatures is a wrapper to get all signatures conveniently
-func LoadAllSignatures() ([]*callgraphv1.Signature, error) {
-	return LoadSignatures("", "", "")
-}
-
 // parse signatures from a given yaml file
-func loadSignatureFile(file string) ([]*callgraphv1.Signature, error) {
-	signatureData, err := signatureFiles.ReadFile(file)
+func loadSignatureFile(source signatureSource, file string) ([]loadedSignature, error) {
+	signatureData, err := fs.ReadFile(source.files, file)
 	if err != nil {
 		log.Errorf("Failed to read signature file: %v", err)
-		return []*callgraphv1.Signature{}, err
+		return []loadedSignature{}, err
 	}
 
 	var parsedSignatureFile signatureFile
 	err = yaml.Unmarshal(signatureData, &parsedSignatureFile)
 	if err != nil {
-		log.Errorf("Failed to parse signature YAML - %s: %v", file, err)
-		return []*callgraphv1.Signature{}, err
+		log.Errorf("Failed to parse signature YAML - %s: %v", sourceFilePath(source, file), err)
+		return []loadedSignature{}, err
 	}
 
-	parsedSignatures := make([]*callgraphv1.Signature, len(parsedSignatureFile.Signatures))
+	// Fields not in the signature schema are ignored when parsing signatures
+	var parsedExtensionFile signatureExtensionFile
+	err = yaml.Unmarshal(signatureData, &parsedExtensionFile)
+	if err != nil {
+		log.Errorf("Failed to parse signature YAML - %s: %v", sourceFilePath(source, file), err)
+		return []loadedSignature{}, err
+	}
+
+	parsedSignatures := make([]loadedSignature, len(parsedSignatureFile.Signatures))
 	for i := range parsedSignatureFile.Signatures {
-		parsedSignatures[i] = &parsedSignatureFile.Signatures[i]
+		parsedSignatures[i] = loadedSignature{
+			signature: &parsedSignatureFile.Signatures[i],
+			service:   parsedExtensionFile.Signatures[i].ServiceMetadata,
+			model:     parsedExtensionFile.Signatures[i].Model,
+			file:      sourceFilePath(source, file),
+		}
 	}
 
 	return parsedSignatures, nil
 }
 
-func checkDuplicateSignatures(signatures []*callgraphv1.Signature) error {
-	signatureMap := make(map[string]bool)
-	for _, signature := range signatures {
-		if _, exists := signatureMap[signature.Id]; exists {
-			return fmt.Errorf("duplicate signature - %s", signature.Id)
+// sourceFilePath returns a human readable path of a signature file within its source
+func sourceFilePath(source signatureSource, file string) string {
+	return filepath.Join(source.name, filepath.FromSlash(file))
+}
+
+func validateServiceMetadata(signatures []loadedSignature) error {
+	for _, loaded := range signatures {
+		for _, endpoint := range loaded.service.Endpoints {
+			if endpoint == "" {
+				return fmt.Errorf("signature %s (defined in %s) has an empty endpoint",
+					loaded.signature.Id, loaded.file)
+			}
+		}
+
+		for _, dataFlow := range loaded.service.DataFlows {
+			if !slices.Contains(dataFlows, dataFlow.Flow) {
+				return fmt.Errorf("signature %s (defined in %s) has an invalid data flow %q, expected one of %s",
+					loaded.signature.Id, loaded.file, dataFlow.Flow, strings.Join(dataFlows, ", "))
+			}
+
+			if dataFlow.Classification == "" {
+				return fmt.Errorf("signature %s (defined in %s) has a data flow without classification",
+					loaded.signature.Id, loaded.file)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkDuplicateSignatures(signatures []loadedSignature) error {
+	signatureMap := make(map[string]string)
+	for _, loaded := range signatures {
+		if existingFile, exists := signatureMap[loaded.signature.Id]; exists {
+			return fmt.Errorf("duplicate signature - %s (defined in %s and %s)",
+				loaded.signature.Id, existingFile, loaded.file)
 		}
-		signatureMap[signature.Id] = true
+		signatureMap[loaded.signature.Id] = loaded.file
 	}
 	return nil
 }
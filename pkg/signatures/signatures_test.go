@@ -1,9 +1,14 @@
 package signatures
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/safedep/xbom/pkg/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSignatures(t *testing.T) {
@@ -12,3 +17,140 @@ func TestSignatures(t *testing.T) {
 	assert.NoError(t, err, "Signatures should be valid")
 	assert.Equal(t, len(sigs), 0, "No signature is actually loaded here")
 }
+
+const testSignatureFileTemplate = `version: 0.1
+signatures:
+  - id: %s
+    description: "Internal SDK client"
+    vendor: "Acme"
+    product: "Internal"
+    service: "SDK"
+    tags: [saas]
+    languages:
+      python:
+        match: any
+        conditions:
+          - type: call
+            value: "acme.sdk.Client"
+`
+
+func writeTestSignatureFile(t *testing.T, dir, relPath, id string) {
+	t.Helper()
+
+	fullPath := filepath.Join(dir, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+	require.NoError(t, os.WriteFile(fullPath, fmt.Appendf(nil, testSignatureFileTemplate, id), 0o644))
+}
+
+func TestLoadAllSignaturesWithDirs(t *testing.T) {
+	t.Run("loads signatures from multiple directories", func(t *testing.T) {
+		dir1 := t.TempDir()
+		dir2 := t.TempDir()
+
+		writeTestSignatureFile(t, dir1, "acme/internal/sdk.yaml", "acme.internal.sdk")
+		writeTestSignatureFile(t, dir2, "acme/billing/api.yml", "acme.billing.api")
+
+		// Non signature files must be ignored
+		require.NoError(t, os.WriteFile(filepath.Join(dir2, "README.md"), []byte("# sigs"), 0o644))
+
+		sigs, err := LoadAllSignaturesWithDirs([]string{dir1, dir2})
+		require.NoError(t, err)
+
+		ids := []string{}
+		for _, sig := range sigs {
+			ids = append(ids, sig.Id)
+		}
+
+		assert.ElementsMatch(t, []string{"acme.internal.sdk", "acme.billing.api"}, ids)
+	})
+
+	t.Run("reports files of duplicate signatures", func(t *testing.T) {
+		dir1 := t.TempDir()
+		dir2 := t.TempDir()
+
+		writeTestSignatureFile(t, dir1, "acme/internal/sdk.yaml", "acme.internal.sdk")
+		writeTestSignatureFile(t, dir2, "acme/other/sdk.yaml", "acme.internal.sdk")
+
+		_, err := LoadAllSignaturesWithDirs([]string{dir1, dir2})
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "acme.internal.sdk")
+		assert.Contains(t, err.Error(), filepath.Join(dir1, "acme", "internal", "sdk.yaml"))
+		assert.Contains(t, err.Error(), filepath.Join(dir2, "acme", "other", "sdk.yaml"))
+	})
+
+	t.Run("fails on missing directory", func(t *testing.T) {
+		_, err := LoadAllSignaturesWithDirs([]string{filepath.Join(t.TempDir(), "missing")})
+		assert.Error(t, err)
+	})
+
+	t.Run("fails on malformed signature file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"),
+			[]byte("version: 0.1\nsignatures: [\n"), 0o644))
+
+		_, err := LoadAllSignaturesWithDirs([]string{dir})
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadAllSignaturesWithMetadata(t *testing.T) {
+	serviceSignature := `version: 0.1
+signatures:
+  - id: acme.billing.api
+    description: "Billing API client"
+    vendor: "Acme"
+    product: "Billing"
+    service: "API client"
+    tags: [saas]
+    endpoints:
+      - "https://billing.acme.com/v1"
+    trust_boundary: false
+    data_flows:
+      - flow: %s
+        classification: "invoices"
+    model:
+      name: "Forecaster"
+      task: "time-series-forecasting"
+    languages:
+      python:
+        match: any
+        conditions:
+          - type: call
+            value: "acme.billing.Client"
+`
+
+	t.Run("loads service and model metadata of signatures", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestSignatureFile(t, dir, "acme/internal/sdk.yaml", "acme.internal.sdk")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "billing.yaml"),
+			fmt.Appendf(nil, serviceSignature, "outbound"), 0o644))
+
+		sigs, metadata, err := LoadAllSignaturesWithMetadata([]string{dir})
+		require.NoError(t, err)
+		assert.Len(t, sigs, 2)
+
+		require.Len(t, metadata.Services, 1, "only signatures with service metadata are included")
+		service := metadata.Services["acme.billing.api"]
+		assert.Equal(t, []string{"https://billing.acme.com/v1"}, service.Endpoints)
+		require.NotNil(t, service.TrustBoundary)
+		assert.False(t, *service.TrustBoundary)
+		require.Len(t, service.DataFlows, 1)
+		assert.Equal(t, "outbound", service.DataFlows[0].Flow)
+		assert.Equal(t, "invoices", service.DataFlows[0].Classification)
+
+		assert.Equal(t, map[string]common.ModelMetadata{
+			"acme.billing.api": {Name: "Forecaster", Task: "time-series-forecasting"},
+		}, metadata.Models)
+	})
+
+	t.Run("fails on invalid data flow", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "billing.yaml"),
+			fmt.Appendf(nil, serviceSignature, "sideways"), 0o644))
+
+		_, _, err := LoadAllSignaturesWithMetadata([]string{dir})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sideways")
+	})
+}
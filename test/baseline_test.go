@@ -0,0 +1,82 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/baseline"
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countEvidences(matchResults map[string][]common.EnrichedSignatureMatchResult) int {
+	count := 0
+	for _, results := range matchResults {
+		for _, result := range results {
+			for _, condition := range result.MatchedConditions {
+				count += len(condition.Evidences)
+			}
+		}
+	}
+
+	return count
+}
+
+func TestBaselineSuppressionE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadSignatures("lang/golang", "", "")
+	require.NoError(t, err)
+
+	fixturePath, err := filepath.Abs("fixtures/test_go_capabilities")
+	require.NoError(t, err)
+
+	tool := common.ToolMetadata{Name: "xbom-test", Version: "test"}
+
+	runWorkflow := func(suppressors []codeanalysis.Suppressor) *common.CodeAnalysisFindings {
+		workflow := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              tool,
+				SourcePath:        fixturePath,
+				SignaturesToMatch: signaturesToMatch,
+				Suppressors:       suppressors,
+			},
+			nil,
+		)
+
+		findings, err := workflow.Execute()
+		require.NoError(t, err)
+
+		return findings
+	}
+
+	initialFindings := runWorkflow(nil)
+	totalEvidences := countEvidences(initialFindings.SignatureWiseMatchResults)
+	require.Greater(t, totalEvidences, 0)
+	assert.Empty(t, initialFindings.SuppressedMatchResults)
+
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, baseline.NewBaseline(tool, fixturePath, initialFindings).Write(baselinePath))
+
+	loadedBaseline, err := baseline.LoadBaseline(baselinePath)
+	require.NoError(t, err)
+
+	suppressedFindings := runWorkflow([]codeanalysis.Suppressor{
+		baseline.NewSuppressor(loadedBaseline, fixturePath),
+	})
+
+	assert.Empty(t, suppressedFindings.SignatureWiseMatchResults)
+	assert.Equal(t, totalEvidences, countEvidences(suppressedFindings.SuppressedMatchResults))
+
+	for _, results := range suppressedFindings.SuppressedMatchResults {
+		for _, result := range results {
+			require.NotNil(t, result.Suppression)
+			assert.Equal(t, baseline.SuppressionSource, result.Suppression.Source)
+		}
+	}
+
+	// Baseline written from a suppressed run retains all findings
+	rewrittenBaseline := baseline.NewBaseline(tool, fixturePath, suppressedFindings)
+	assert.Equal(t, loadedBaseline.Findings, rewrittenBaseline.Findings)
+}
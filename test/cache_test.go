@@ -0,0 +1,88 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/code/plugin/callgraph"
+	"github.com/safedep/xbom/pkg/cache"
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evidenceMetadata(matchResults map[string][]common.EnrichedSignatureMatchResult) map[string][]callgraph.EvidenceMetadata {
+	metadata := map[string][]callgraph.EvidenceMetadata{}
+	for signatureID, results := range matchResults {
+		for _, result := range results {
+			for i, condition := range result.MatchedConditions {
+				for j := range condition.Evidences {
+					metadata[signatureID] = append(metadata[signatureID], result.Metadata(i, j))
+				}
+			}
+		}
+	}
+
+	return metadata
+}
+
+func TestAnalysisCacheE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	corpusDir := createFixtureCorpus(t, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(corpusDir, "suppressed.go"), []byte(inlineSuppressionFixture), 0o644))
+
+	tool := common.ToolMetadata{Name: "xbom-test", Version: "test"}
+
+	fileCache, err := cache.NewFileCache(cache.FileCacheConfig{
+		Dir:        t.TempDir(),
+		Tool:       tool,
+		Signatures: signaturesToMatch,
+	})
+	require.NoError(t, err)
+
+	runWorkflow := func() *common.CodeAnalysisFindings {
+		findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              tool,
+				SourcePath:        corpusDir,
+				SignaturesToMatch: signaturesToMatch,
+				Suppressors:       []codeanalysis.Suppressor{codeanalysis.NewInlineSuppressor()},
+				Cache:             fileCache,
+			},
+			nil,
+		).Execute()
+		require.NoError(t, err)
+
+		return findings
+	}
+
+	initialFindings := runWorkflow()
+	require.NotEmpty(t, initialFindings.SignatureWiseMatchResults)
+	require.NotEmpty(t, initialFindings.SuppressedMatchResults)
+	assert.Equal(t, 0, initialFindings.Stats.CachedFiles)
+
+	cachedFindings := runWorkflow()
+	assert.Greater(t, cachedFindings.Stats.CachedFiles, 0)
+	assert.Equal(t, evidenceMetadata(initialFindings.SignatureWiseMatchResults),
+		evidenceMetadata(cachedFindings.SignatureWiseMatchResults))
+	assert.Equal(t, evidenceMetadata(initialFindings.SuppressedMatchResults),
+		evidenceMetadata(cachedFindings.SuppressedMatchResults))
+
+	// Only the modified file is analysed again
+	modifiedFile := filepath.Join(corpusDir, "copy-000", "fixtures", "test_go_capabilities", "main.go")
+	content, err := os.ReadFile(modifiedFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(modifiedFile, append(content, []byte("\n// modified\n")...), 0o644))
+
+	modifiedFindings := runWorkflow()
+	assert.Equal(t, cachedFindings.Stats.CachedFiles-1, modifiedFindings.Stats.CachedFiles)
+	assert.Equal(t, evidenceMetadata(initialFindings.SignatureWiseMatchResults),
+		evidenceMetadata(modifiedFindings.SignatureWiseMatchResults))
+	assert.Equal(t, evidenceMetadata(initialFindings.SuppressedMatchResults),
+		evidenceMetadata(modifiedFindings.SuppressedMatchResults))
+}
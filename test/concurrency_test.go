@@ -0,0 +1,127 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	callgraphv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/code/callgraph/v1"
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	_ "github.com/safedep/xbom/signatures" // Initialize embedded signatures
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFixtureCorpus copies the fixtures into a temporary directory as many times
+// as requested to create a source tree large enough for concurrent analysis
+func createFixtureCorpus(tb testing.TB, copies int) string {
+	tb.Helper()
+
+	corpusDir := tb.TempDir()
+	for i := 0; i < copies; i++ {
+		err := filepath.WalkDir("fixtures", func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			target := filepath.Join(corpusDir, fmt.Sprintf("copy-%03d", i), path)
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+
+			return os.WriteFile(target, content, 0o644)
+		})
+		require.NoError(tb, err)
+	}
+
+	return corpusDir
+}
+
+func analyseCorpus(tb testing.TB, corpusDir string, signaturesToMatch []*callgraphv1.Signature,
+	concurrency int,
+) *common.CodeAnalysisFindings {
+	tb.Helper()
+
+	findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+			SourcePath:        corpusDir,
+			SignaturesToMatch: signaturesToMatch,
+			Concurrency:       concurrency,
+		},
+		nil,
+	).Execute()
+	require.NoError(tb, err)
+
+	return findings
+}
+
+// findingLocations flattens the findings of a signature to comparable strings
+// in the order of the match results
+func findingLocations(results []common.EnrichedSignatureMatchResult) []string {
+	locations := []string{}
+	for _, result := range results {
+		for i, condition := range result.MatchedConditions {
+			for j := range condition.Evidences {
+				metadata := result.Metadata(i, j)
+
+				line := 0
+				if metadata.CallerIdentifierMetadata != nil {
+					line = int(metadata.CallerIdentifierMetadata.StartLine) + 1
+				}
+
+				locations = append(locations, fmt.Sprintf("%s:%d %s", result.FilePath, line, condition.Condition.GetValue()))
+			}
+		}
+	}
+
+	return locations
+}
+
+func TestConcurrentAnalysisIsDeterministic(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	corpusDir := createFixtureCorpus(t, 10)
+
+	sequential := analyseCorpus(t, corpusDir, signaturesToMatch, 1)
+	require.NotEmpty(t, sequential.SignatureWiseMatchResults)
+
+	for _, concurrency := range []int{2, 8} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			concurrent := analyseCorpus(t, corpusDir, signaturesToMatch, concurrency)
+
+			require.Len(t, concurrent.SignatureWiseMatchResults, len(sequential.SignatureWiseMatchResults))
+			for signatureID, results := range sequential.SignatureWiseMatchResults {
+				assert.Equal(t, findingLocations(results),
+					findingLocations(concurrent.SignatureWiseMatchResults[signatureID]), signatureID)
+			}
+		})
+	}
+}
+
+// BenchmarkCodeAnalysisWorkflow compares sequential and concurrent analysis
+//
+//	go test ./test -run '^$' -bench CodeAnalysisWorkflow
+func BenchmarkCodeAnalysisWorkflow(b *testing.B) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(b, err)
+
+	corpusDir := createFixtureCorpus(b, 50)
+
+	for _, concurrency := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency-%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				analyseCorpus(b, corpusDir, signaturesToMatch, concurrency)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/gitdiff"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const differentialBaseFixture = `package main
+
+import "crypto/sha256"
+
+func main() {
+	sha256.Sum256([]byte("data"))
+}
+`
+
+const differentialChangedFixture = `package main
+
+import (
+	"crypto/sha256"
+	"net/http"
+)
+
+func main() {
+	http.Get("https://example.com")
+	sha256.Sum256([]byte("data"))
+	sha256.Sum256([]byte("more data"))
+}
+`
+
+const differentialUnchangedFixture = `package main
+
+import "os"
+
+func env() string {
+	return os.Getenv("HOME")
+}
+`
+
+func TestDifferentialScanE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadSignatures("lang/golang", "", "")
+	require.NoError(t, err)
+
+	sourcePath := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(sourcePath, name), []byte(content), 0o644))
+	}
+
+	repository, err := git.PlainInit(sourcePath, false)
+	require.NoError(t, err)
+
+	worktree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	writeFile("main.go", differentialBaseFixture)
+	writeFile("env.go", differentialUnchangedFixture)
+	require.NoError(t, worktree.AddWithOptions(&git.AddOptions{All: true}))
+
+	_, err = worktree.Commit("base", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName("feature"),
+		Create: true,
+	}))
+
+	writeFile("main.go", differentialChangedFixture)
+
+	changes, err := gitdiff.ChangedFiles(sourcePath, "master")
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.go"}, changes.Files)
+
+	baseDir := t.TempDir()
+	require.NoError(t, changes.ExtractBase(baseDir))
+
+	tool := common.ToolMetadata{Name: "xbom-test", Version: "test"}
+
+	baseFindings, err := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              tool,
+			SourcePath:        baseDir,
+			SignaturesToMatch: signaturesToMatch,
+		},
+		nil,
+	).Execute()
+	require.NoError(t, err)
+
+	findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              tool,
+			SourcePath:        sourcePath,
+			SignaturesToMatch: signaturesToMatch,
+			PathFilter:        changes.Contains,
+			DiffClassifier:    gitdiff.NewClassifier(baseFindings, baseDir, sourcePath),
+		},
+		nil,
+	).Execute()
+	require.NoError(t, err)
+
+	// Unchanged files are not analysed
+	assert.NotContains(t, findings.SignatureWiseMatchResults, "golang.environment.read")
+
+	evidenceCount := func(signatureId string, status common.DiffStatus) int {
+		count := 0
+		for _, result := range findings.SignatureWiseMatchResults[signatureId] {
+			if result.DiffStatus != status {
+				continue
+			}
+
+			for _, condition := range result.MatchedConditions {
+				count += len(condition.Evidences)
+			}
+		}
+
+		return count
+	}
+
+	assert.Equal(t, 1, evidenceCount("golang.network.http.client", common.DiffStatusIntroduced))
+	assert.Equal(t, 0, evidenceCount("golang.network.http.client", common.DiffStatusPreExisting))
+
+	// The moved call is pre-existing while the additional call is introduced
+	assert.Equal(t, 1, evidenceCount("golang.crypto.hash", common.DiffStatusPreExisting))
+	assert.Equal(t, 1, evidenceCount("golang.crypto.hash", common.DiffStatusIntroduced))
+}
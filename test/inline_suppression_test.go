@@ -0,0 +1,85 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inlineSuppressionFixture = `package main
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"net/http"
+)
+
+func main() {
+	// Checksums only, not used for security
+	// xbom:ignore golang.crypto.hash reason="checksum only"
+	sha256.Sum256([]byte("data"))
+
+	aes.NewCipher([]byte("key")) // xbom:ignore golang.crypto.*
+
+	// xbom:ignore golang.crypto.hash
+	http.Get("https://example.com")
+}
+`
+
+func TestInlineSuppressionE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadSignatures("lang/golang", "", "")
+	require.NoError(t, err)
+
+	sourcePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "main.go"), []byte(inlineSuppressionFixture), 0o644))
+
+	runWorkflow := func(suppressors []codeanalysis.Suppressor) *common.CodeAnalysisFindings {
+		workflow := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+				SourcePath:        sourcePath,
+				SignaturesToMatch: signaturesToMatch,
+				Suppressors:       suppressors,
+			},
+			nil,
+		)
+
+		findings, err := workflow.Execute()
+		require.NoError(t, err)
+
+		return findings
+	}
+
+	unsuppressedFindings := runWorkflow(nil)
+	require.Contains(t, unsuppressedFindings.SignatureWiseMatchResults, "golang.crypto.hash")
+	require.Contains(t, unsuppressedFindings.SignatureWiseMatchResults, "golang.crypto.aes")
+	require.Contains(t, unsuppressedFindings.SignatureWiseMatchResults, "golang.network.http.client")
+
+	findings := runWorkflow([]codeanalysis.Suppressor{codeanalysis.NewInlineSuppressor()})
+
+	assert.NotContains(t, findings.SignatureWiseMatchResults, "golang.crypto.hash")
+	assert.NotContains(t, findings.SignatureWiseMatchResults, "golang.crypto.aes")
+
+	// Directive for another signature does not suppress the call below it
+	assert.Contains(t, findings.SignatureWiseMatchResults, "golang.network.http.client")
+
+	require.Contains(t, findings.SuppressedMatchResults, "golang.crypto.hash")
+	for _, result := range findings.SuppressedMatchResults["golang.crypto.hash"] {
+		require.NotNil(t, result.Suppression)
+		assert.Equal(t, codeanalysis.InlineSuppressionSource, result.Suppression.Source)
+		assert.Equal(t, "checksum only", result.Suppression.Reason)
+	}
+
+	require.Contains(t, findings.SuppressedMatchResults, "golang.crypto.aes")
+	for _, result := range findings.SuppressedMatchResults["golang.crypto.aes"] {
+		require.NotNil(t, result.Suppression)
+		assert.Equal(t, codeanalysis.InlineSuppressionSource, result.Suppression.Source)
+		assert.Empty(t, result.Suppression.Reason)
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSyntheticCorpus creates files of about fileSize bytes with only a
+// few matches each, so that the findings are small compared to the sources
+func createSyntheticCorpus(tb testing.TB, files, fileSize int) string {
+	tb.Helper()
+
+	padding := strings.Repeat("# "+strings.Repeat("x", 62)+"\n", fileSize/65)
+
+	corpusDir := tb.TempDir()
+	for i := 0; i < files; i++ {
+		content := fmt.Sprintf("import hashlib\nimport subprocess\n\n%s\n"+
+			"def work_%d():\n    hashlib.sha256(b\"data\")\n    subprocess.run([\"ls\"])\n", padding, i)
+
+		target := filepath.Join(corpusDir, fmt.Sprintf("module_%04d.py", i))
+		require.NoError(tb, os.WriteFile(target, []byte(content), 0o644))
+	}
+
+	return corpusDir
+}
+
+func TestFindingsDoNotRetainTreeData(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	findings := analyseCorpus(t, createFixtureCorpus(t, 1), signaturesToMatch, 2)
+	require.NotEmpty(t, findings.SignatureWiseMatchResults)
+
+	for signatureID, results := range findings.SignatureWiseMatchResults {
+		for _, result := range results {
+			assert.Nil(t, result.TreeData, signatureID)
+
+			for i, condition := range result.MatchedConditions {
+				require.Len(t, result.EvidenceMetadata[i], len(condition.Evidences), signatureID)
+
+				for j, evidence := range condition.Evidences {
+					assert.Nil(t, evidence.CallerIdentifier, signatureID)
+					assert.Nil(t, evidence.Caller.TreeNode, signatureID)
+					assert.Nil(t, evidence.Callee.TreeNode, signatureID)
+					assert.NotNil(t, result.Metadata(i, j).CallerIdentifierMetadata, signatureID)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkCodeAnalysisMemory reports the heap retained by the findings of large
+// files with few matches. It stays bounded by the number of matches, not by the
+// size of the sources.
+//
+//	go test ./test -run '^$' -bench CodeAnalysisMemory -benchtime 1x
+func BenchmarkCodeAnalysisMemory(b *testing.B) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(b, err)
+
+	const fileSize = 256 * 1024
+
+	for _, files := range []int{25, 100} {
+		b.Run(fmt.Sprintf("files-%d", files), func(b *testing.B) {
+			corpusDir := createSyntheticCorpus(b, files, fileSize)
+
+			var retained uint64
+			for i := 0; i < b.N; i++ {
+				var before, after runtime.MemStats
+
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+
+				findings := analyseCorpus(b, corpusDir, signaturesToMatch, 4)
+
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				runtime.KeepAlive(findings)
+
+				if after.HeapAlloc > before.HeapAlloc {
+					retained = after.HeapAlloc - before.HeapAlloc
+				}
+			}
+
+			b.ReportMetric(float64(retained)/float64(files), "retained-B/file")
+			b.ReportMetric(float64(fileSize), "source-B/file")
+		})
+	}
+}
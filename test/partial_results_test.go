@@ -0,0 +1,91 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialResultsE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	corpusDir := createFixtureCorpus(t, 1)
+	brokenPath := filepath.Join(corpusDir, "broken.py")
+	require.NoError(t, os.Symlink(filepath.Join(corpusDir, "missing.py"), brokenPath))
+
+	complete := analyseCorpus(t, createFixtureCorpus(t, 1), signaturesToMatch, 2)
+
+	t.Run("fails without partial results", func(t *testing.T) {
+		_, err := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+				SourcePath:        corpusDir,
+				SignaturesToMatch: signaturesToMatch,
+			},
+			nil,
+		).Execute()
+		assert.ErrorIs(t, err, codeanalysis.ErrReadSourceFile)
+	})
+
+	t.Run("records failed files with partial results", func(t *testing.T) {
+		var failed []common.FailedFile
+
+		findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+				SourcePath:        corpusDir,
+				SignaturesToMatch: signaturesToMatch,
+				Concurrency:       2,
+				PartialResults:    true,
+				Callbacks: codeanalysis.CodeAnalysisCallbackRegistry{
+					OnFileFailed: func(failedFile common.FailedFile) {
+						failed = append(failed, failedFile)
+					},
+				},
+			},
+			nil,
+		).Execute()
+		require.NoError(t, err)
+
+		assert.True(t, findings.Partial())
+		require.Len(t, findings.FailedFiles, 1)
+		assert.Equal(t, brokenPath, findings.FailedFiles[0].Path)
+		assert.ErrorIs(t, findings.FailedFiles[0].Err, codeanalysis.ErrReadSourceFile)
+		assert.Equal(t, findings.FailedFiles, failed)
+
+		// Findings of other files are complete
+		require.Len(t, findings.SignatureWiseMatchResults, len(complete.SignatureWiseMatchResults))
+		for signatureID, results := range complete.SignatureWiseMatchResults {
+			assert.Len(t, findings.SignatureWiseMatchResults[signatureID], len(results), signatureID)
+		}
+	})
+
+	t.Run("fails on callback errors with partial results", func(t *testing.T) {
+		callbackErr := errors.New("callback failed")
+
+		_, err := codeanalysis.NewCodeAnalysisWorkflow(
+			codeanalysis.CodeAnalysisWorkflowConfig{
+				Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+				SourcePath:        corpusDir,
+				SignaturesToMatch: signaturesToMatch,
+				PartialResults:    true,
+				Callbacks: codeanalysis.CodeAnalysisCallbackRegistry{
+					OnMatch: func(common.EnrichedSignatureMatchResult) error {
+						return callbackErr
+					},
+				},
+			},
+			nil,
+		).Execute()
+		assert.ErrorIs(t, err, codeanalysis.ErrOnMatchCallback)
+		assert.ErrorIs(t, err, callbackErr)
+	})
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressCallbacksE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	corpusDir := createFixtureCorpus(t, 3)
+	require.NoError(t, os.WriteFile(filepath.Join(corpusDir, "large.py"),
+		[]byte(strings.Repeat("# padding\n", 1000)), 0o644))
+
+	total := -1
+	started := map[string]bool{}
+	done := map[string]bool{}
+	skipped := map[string]bool{}
+	matches := 0
+
+	findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+			SourcePath:        corpusDir,
+			SignaturesToMatch: signaturesToMatch,
+			Concurrency:       4,
+			MaxFileSize:       5000,
+			Callbacks: codeanalysis.CodeAnalysisCallbackRegistry{
+				OnFilesEnumerated: func(count int) {
+					assert.Empty(t, started, "files enumerated after analysis started")
+					total = count
+				},
+				OnFileStart: func(filePath string) {
+					started[filePath] = true
+				},
+				OnFileDone: func(filePath string) {
+					assert.True(t, started[filePath], filePath)
+					done[filePath] = true
+				},
+				OnFileSkipped: func(skippedFile common.SkippedFile) {
+					assert.True(t, started[skippedFile.Path], skippedFile.Path)
+					skipped[skippedFile.Path] = true
+				},
+				OnMatch: func(result common.EnrichedSignatureMatchResult) error {
+					assert.True(t, started[result.FilePath], result.FilePath)
+					assert.False(t, done[result.FilePath], result.FilePath)
+					matches++
+					return nil
+				},
+			},
+		},
+		nil,
+	).Execute()
+	require.NoError(t, err)
+
+	assert.Greater(t, total, 0)
+	assert.Len(t, started, total)
+	assert.Len(t, done, total-1)
+	assert.Equal(t, map[string]bool{filepath.Join(corpusDir, "large.py"): true}, skipped)
+
+	totalMatches := 0
+	for _, results := range findings.SignatureWiseMatchResults {
+		totalMatches += len(results)
+	}
+	assert.Greater(t, matches, 0)
+	assert.Equal(t, totalMatches, matches)
+}
+
+func TestOnMatchCallbackErrorE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	fixturePath, err := filepath.Abs("fixtures/test_go_capabilities")
+	require.NoError(t, err)
+
+	streamErr := errors.New("stream closed")
+
+	_, err = codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+			SourcePath:        fixturePath,
+			SignaturesToMatch: signaturesToMatch,
+			Callbacks: codeanalysis.CodeAnalysisCallbackRegistry{
+				OnMatch: func(common.EnrichedSignatureMatchResult) error {
+					return streamErr
+				},
+			},
+		},
+		nil,
+	).Execute()
+	assert.ErrorIs(t, err, codeanalysis.ErrOnMatchCallback)
+	assert.ErrorIs(t, err, streamErr)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/xbom/pkg/codeanalysis"
+	"github.com/safedep/xbom/pkg/common"
+	"github.com/safedep/xbom/pkg/reporter"
+	"github.com/safedep/xbom/pkg/signatures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStreamingReporter struct {
+	streamed   map[string][]common.EnrichedSignatureMatchResult
+	suppressed map[string][]common.EnrichedSignatureMatchResult
+	recorded   bool
+	finished   bool
+	streamedAt []bool
+}
+
+var _ reporter.StreamingReporter = (*testStreamingReporter)(nil)
+
+func (r *testStreamingReporter) Name() string {
+	return "test-streaming"
+}
+
+func (r *testStreamingReporter) RecordMatchResults(results []common.EnrichedSignatureMatchResult) error {
+	r.streamedAt = append(r.streamedAt, r.recorded)
+
+	for _, result := range results {
+		target := r.streamed
+		if result.Suppression != nil {
+			target = r.suppressed
+		}
+
+		target[result.MatchedSignature.GetId()] = append(target[result.MatchedSignature.GetId()], result)
+	}
+
+	return nil
+}
+
+func (r *testStreamingReporter) RecordCodeAnalysisFindings(_ *common.CodeAnalysisFindings) error {
+	r.recorded = true
+	return nil
+}
+
+func (r *testStreamingReporter) Finish() error {
+	r.finished = true
+	return nil
+}
+
+func TestStreamingReporterE2E(t *testing.T) {
+	signaturesToMatch, err := signatures.LoadAllSignatures()
+	require.NoError(t, err)
+
+	corpusDir := createFixtureCorpus(t, 2)
+	require.NoError(t, os.WriteFile(filepath.Join(corpusDir, "suppressed.go"), []byte(inlineSuppressionFixture), 0o644))
+
+	streamingReporter := &testStreamingReporter{
+		streamed:   map[string][]common.EnrichedSignatureMatchResult{},
+		suppressed: map[string][]common.EnrichedSignatureMatchResult{},
+	}
+
+	findings, err := codeanalysis.NewCodeAnalysisWorkflow(
+		codeanalysis.CodeAnalysisWorkflowConfig{
+			Tool:              common.ToolMetadata{Name: "xbom-test", Version: "test"},
+			SourcePath:        corpusDir,
+			SignaturesToMatch: signaturesToMatch,
+			Suppressors:       []codeanalysis.Suppressor{codeanalysis.NewInlineSuppressor()},
+			Concurrency:       4,
+		},
+		[]reporter.Reporter{streamingReporter},
+	).Execute()
+	require.NoError(t, err)
+
+	require.NotEmpty(t, streamingReporter.streamedAt)
+	assert.NotContains(t, streamingReporter.streamedAt, true, "results streamed after all findings were recorded")
+	assert.True(t, streamingReporter.recorded)
+	assert.True(t, streamingReporter.finished)
+
+	// Streamed results are the findings in the order files are analysed
+	require.NotEmpty(t, findings.SuppressedMatchResults)
+	assert.ElementsMatch(t, findingLocations(flattenResults(findings.SignatureWiseMatchResults)),
+		findingLocations(flattenResults(streamingReporter.streamed)))
+	assert.ElementsMatch(t, findingLocations(flattenResults(findings.SuppressedMatchResults)),
+		findingLocations(flattenResults(streamingReporter.suppressed)))
+}
+
+func flattenResults(matchResults map[string][]common.EnrichedSignatureMatchResult) []common.EnrichedSignatureMatchResult {
+	results := []common.EnrichedSignatureMatchResult{}
+	for _, signatureMatchResults := range matchResults {
+		results = append(results, signatureMatchResults...)
+	}
+
+	return results
+}